@@ -4,11 +4,15 @@ import (
 	"flag"
 	"fmt"
 	"github.com/BiathlonRaceProto-Yadro/internal/application"
+	"github.com/BiathlonRaceProto-Yadro/internal/i18n"
 	"github.com/BiathlonRaceProto-Yadro/internal/infrastructure/config"
 	"github.com/BiathlonRaceProto-Yadro/internal/infrastructure/event_parser"
+	"github.com/BiathlonRaceProto-Yadro/internal/infrastructure/registry"
+	"github.com/BiathlonRaceProto-Yadro/internal/infrastructure/results"
 	"github.com/BiathlonRaceProto-Yadro/internal/logging"
 	"log/slog"
 	"os"
+	"strconv"
 )
 
 func main() {
@@ -16,21 +20,172 @@ func main() {
 	logInfo := flag.Bool("info", false, "Enable info logs")
 	logError := flag.Bool("error", false, "Enable error logs")
 	fullOutput := flag.Bool("fullOutput", false, "Generate full report")
+	progression := flag.Bool("progression", false, "Add rank and gap to leader after every lap and range")
+	netLaps := flag.Bool("netLaps", false, "Show net lap times excluding penalty loops next to raw times")
+	excludeRange := flag.Bool("excludeRange", false, "Exclude range time from net lap times")
+	athletesPath := flag.String("athletes", "", "Athlete registry file (.csv or .json)")
+	overall := flag.Bool("overall", false, "Add an overall ranking across all categories")
+	filter := flag.String("filter", "", "Show only athletes matching field=value (bib, name, nation, club, birthYear, category)")
+	championship := flag.String("championship", "", "Championship manifest (.json) listing races to process in parallel")
+	format := flag.String("format", application.OutputText, "Output format: text, json, csv, tsv, html or xml")
+	layout := flag.String("layout", application.LayoutWide, "CSV/TSV layout: wide (row per athlete) or long (row per lap, penalty lap and stage)")
+	delimiter := flag.String("delimiter", "", "CSV/TSV column delimiter (single character or tab)")
+	charts := flag.Bool("charts", false, "Embed SVG charts into the HTML report")
+	chartsDir := flag.String("chartsDir", "", "Write SVG charts as standalone files into this directory")
+	templatePath := flag.String("template", "", "Render the results table of each classification with a text/template file")
+	shootingStats := flag.Bool("shootingStats", false, "Add shooting statistics per stage, range, target and athlete")
+	lang := flag.String("lang", i18n.DefaultLang, "Language of reports and race log: built-in code (en, ru) or path to a message catalog (.json)")
+	tolerance := flag.Duration("tolerance", 0, "Allowed time difference for diff (e.g. 500ms); larger differences exit with code 2")
 	flag.Parse()
 
 	logger := logging.СonfigureLogger(*logDebug, *logInfo, *logError)
 
 	args := flag.Args()
-	if len(args) != 2 {
+	standings := len(args) > 0 && args[0] == "standings"
+	athleteCard := len(args) > 0 && args[0] == "athlete"
+	diff := len(args) > 0 && args[0] == "diff"
+	switch {
+	case standings && len(args) != 2:
+		logger.Error("Usage: main.go [flags] standings <manifest_path>", "argsCount", len(args))
+		os.Exit(1)
+	case athleteCard && len(args) != 4:
+		logger.Error("Usage: main.go [flags] athlete <id> <config_path> <events_path>", "argsCount", len(args))
+		os.Exit(1)
+	case diff && len(args) != 3 && len(args) != 4:
+		logger.Error("Usage: main.go [flags] diff <config_path> <events_before> <events_after> | diff <results_before> <results_after>", "argsCount", len(args))
+		os.Exit(1)
+	case !standings && !athleteCard && !diff && *championship == "" && len(args) != 2:
 		logger.Error("Usage: main.go [flags] <config_path> <events_path>", "argsCount", len(args))
 		os.Exit(1)
 	}
-	configPath := args[0]
-	eventsPath := args[1]
 
-	app := initializeApp(logger)
+	catalog, err := i18n.Load(*lang)
+	if err != nil {
+		logger.Error("Invalid language", "lang", *lang, "error", err)
+		os.Exit(1)
+	}
+	app := initializeApp(logger, catalog)
+
+	options := application.ReportOptions{
+		FullOutput:    *fullOutput,
+		Progression:   *progression,
+		NetLaps:       *netLaps,
+		ExcludeRange:  *excludeRange,
+		Overall:       *overall,
+		Charts:        *charts,
+		ChartsDir:     *chartsDir,
+		ShootingStats: *shootingStats,
+		Tolerance:     *tolerance,
+		Catalog:       catalog,
+	}
+	outputFormat, err := application.ParseOutputFormat(*format)
+	if err != nil {
+		logger.Error("Invalid output format", "format", *format, "error", err)
+		os.Exit(1)
+	}
+	options.Format = outputFormat
+	if options.Layout, err = application.ParseLayout(*layout); err != nil {
+		logger.Error("Invalid layout", "layout", *layout, "error", err)
+		os.Exit(1)
+	}
+	if *delimiter != "" {
+		if options.Delimiter, err = application.ParseDelimiter(*delimiter); err != nil {
+			logger.Error("Invalid delimiter", "delimiter", *delimiter, "error", err)
+			os.Exit(1)
+		}
+	}
+	if *templatePath != "" {
+		if options.Template, err = application.ParseReportTemplate(*templatePath); err != nil {
+			logger.Error("Invalid report template", "path", *templatePath, "error", err)
+			os.Exit(1)
+		}
+	}
+	if *filter != "" {
+		f, err := application.ParseResultFilter(*filter)
+		if err != nil {
+			logger.Error("Invalid filter", "filter", *filter, "error", err)
+			os.Exit(1)
+		}
+		options.Filter = f
+	}
+
+	if standings {
+		if options.Format != application.OutputText {
+			logger.Error("Standings support text output only", "format", options.Format)
+			os.Exit(1)
+		}
+		report, err := app.RunStandings(args[1], options)
+		if err != nil {
+			logger.Error("Standings failed", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Standings completed successfully")
+		fmt.Println(report)
+		return
+	}
+
+	if athleteCard {
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			logger.Error("Invalid competitor id", "id", args[1], "error", err)
+			os.Exit(1)
+		}
+		if options.Format != application.OutputText && options.Format != application.OutputJSON {
+			logger.Error("Athlete card supports text and json output only", "format", options.Format)
+			os.Exit(1)
+		}
+		report, err := app.RunAthleteCard(args[2], args[3], *athletesPath, id, options)
+		if err != nil {
+			logger.Error("Athlete card failed", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Athlete card completed successfully")
+		fmt.Println(report)
+		return
+	}
+
+	if diff {
+		if options.Format != application.OutputText && options.Format != application.OutputJSON {
+			logger.Error("Diff supports text and json output only", "format", options.Format)
+			os.Exit(1)
+		}
+		var report string
+		var exceeded bool
+		if len(args) == 4 {
+			report, exceeded, err = app.RunDiff(args[1], args[2], args[3], *athletesPath, options)
+		} else {
+			report, exceeded, err = app.RunResultDiff(args[1], args[2], options)
+		}
+		if err != nil {
+			logger.Error("Diff failed", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Diff completed successfully", "exceeded", exceeded)
+		fmt.Println(report)
+		if exceeded {
+			os.Exit(2)
+		}
+		return
+	}
+
+	if *championship != "" {
+		if options.Format != application.OutputText {
+			logger.Error("Championship supports text output only", "format", options.Format)
+			os.Exit(1)
+		}
+		report, err := app.RunChampionship(*championship, options)
+		if report != "" {
+			fmt.Println(report)
+		}
+		if err != nil {
+			logger.Error("Championship failed", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Championship completed successfully")
+		return
+	}
 
-	report, err := app.Run(configPath, eventsPath, *fullOutput)
+	report, err := app.Run(args[0], args[1], *athletesPath, options)
 	if err != nil {
 		logger.Error("Application failed", "error", err)
 		os.Exit(1)
@@ -40,19 +195,26 @@ func main() {
 	fmt.Println(report)
 }
 
-func initializeApp(logger *slog.Logger) *application.App {
+func initializeApp(logger *slog.Logger, catalog *i18n.Catalog) *application.App {
 	configLoader := config.NewJSONConfigLoader()
+	registryLoader := registry.NewFileRegistryLoader()
+	manifestLoader := config.NewJSONManifestLoader()
+	resultLoader := results.NewFileResultLoader()
 	eventParser := event_parser.NewTextEventParser()
 
 	// Создаём временные заглушки, которые будут перезаписаны в Run()
-	reportService := application.NewReportService(nil, false, logger)
-	processor := application.NewEventProcessor(nil, logger)
+	reportService := application.NewReportService(nil, application.ReportOptions{}, logger)
+	processor := application.NewEventProcessor(nil, nil, catalog, logger)
 
 	return application.NewApp(
 		configLoader,
+		registryLoader,
+		manifestLoader,
+		resultLoader,
 		eventParser,
 		processor,
 		reportService,
+		catalog,
 		logger,
 	)
 }
@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"github.com/BiathlonRaceProto-Yadro/internal/i18n"
 	"log/slog"
 )
 
@@ -10,6 +11,18 @@ type ConfigLoader interface {
 	LoadConfig(path string) (*models.Config, error)
 }
 
+type RegistryLoader interface {
+	LoadRegistry(path string) (*models.Registry, error)
+}
+
+type ManifestLoader interface {
+	LoadManifest(path string) (*models.Championship, error)
+}
+
+type ResultLoader interface {
+	LoadResult(path string) (*RaceResult, error)
+}
+
 type EventParser interface {
 	ParseEvents(path string) ([]models.Event, error)
 }
@@ -17,37 +30,79 @@ type EventParser interface {
 type EventHandler interface {
 	HandleEvent(event models.Event) error
 	GetCompetitors() []*models.Competitor
+	GetJury() *models.Jury
 }
 
 type ReportGenerator interface {
-	GenerateReport(competitors []*models.Competitor, config *models.Config) string
+	GenerateReport(race *models.Race) (string, error)
+}
+
+// ResultRenderer выводит уже собранный результат, например загруженный из файла
+type ResultRenderer interface {
+	RenderResult(result *RaceResult) (string, error)
 }
 
 type App struct {
 	configLoader    ConfigLoader
+	registryLoader  RegistryLoader
+	manifestLoader  ManifestLoader
+	resultLoader    ResultLoader
 	eventParser     EventParser
 	eventProcessor  EventHandler
 	reportGenerator ReportGenerator
+	catalog         *i18n.Catalog // Сообщения журнала гонки и замечаний проверки данных
 	logger          *slog.Logger
 }
 
 func NewApp(
 	configLoader ConfigLoader,
+	registryLoader RegistryLoader,
+	manifestLoader ManifestLoader,
+	resultLoader ResultLoader,
 	eventParser EventParser,
 	processor EventHandler,
 	report ReportGenerator,
+	catalog *i18n.Catalog,
 	logger *slog.Logger,
 ) *App {
 	return &App{
 		configLoader:    configLoader,
+		registryLoader:  registryLoader,
+		manifestLoader:  manifestLoader,
+		resultLoader:    resultLoader,
 		eventParser:     eventParser,
 		eventProcessor:  processor,
 		reportGenerator: report,
+		catalog:         catalog,
 		logger:          logger,
 	}
 }
 
-func (a *App) Run(configPath, eventsPath string, fullOutput bool) (string, error) {
+// Run обрабатывает одну гонку. athletesPath - необязательный путь к реестру спортсменов.
+func (a *App) Run(configPath, eventsPath, athletesPath string, options ReportOptions) (string, error) {
+	race, err := a.processRace(configPath, eventsPath, athletesPath, false)
+	if err != nil {
+		return "", err
+	}
+
+	if options.ChartsDir != "" {
+		a.logger.Info("Writing charts", "dir", options.ChartsDir)
+		if err := WriteCharts(options.ChartsDir, RaceCharts(race, options)); err != nil {
+			a.logger.Error("Failed to write charts", "dir", options.ChartsDir, "error", err)
+			return "", err
+		}
+	}
+
+	// Генерация отчёта
+	a.reportGenerator = NewReportGenerator(race.Config, options, a.logger)
+	a.logger.Info("Generating final report")
+	return a.reportGenerator.GenerateReport(race)
+}
+
+// processRace загружает конфигурацию, реестр и события гонки и возвращает её итоговое состояние.
+// Обычно первое отклонённое событие прерывает обработку; при lenient оно только записывается
+// замечанием в хронологию участника, а обработка продолжается.
+func (a *App) processRace(configPath, eventsPath, athletesPath string, lenient bool) (*models.Race, error) {
 	// Загрузка конфигурации
 	if a.logger.Enabled(context.Background(), slog.LevelDebug) {
 		a.logger.Debug("Loading configuration", "path", configPath)
@@ -55,11 +110,23 @@ func (a *App) Run(configPath, eventsPath string, fullOutput bool) (string, error
 	config, err := a.configLoader.LoadConfig(configPath)
 	if err != nil {
 		a.logger.Error("Failed to load config", "path", configPath, "error", err)
-		return "", err
+		return nil, err
+	}
+
+	// Загрузка реестра спортсменов
+	var registry *models.Registry
+	if athletesPath != "" {
+		if a.logger.Enabled(context.Background(), slog.LevelDebug) {
+			a.logger.Debug("Loading athlete registry", "path", athletesPath)
+		}
+		registry, err = a.registryLoader.LoadRegistry(athletesPath)
+		if err != nil {
+			a.logger.Error("Failed to load athlete registry", "path", athletesPath, "error", err)
+			return nil, err
+		}
 	}
 
-	a.reportGenerator = NewReportService(config, fullOutput, a.logger)
-	a.eventProcessor = NewEventProcessor(config, a.logger)
+	a.eventProcessor = NewEventProcessor(config, registry, a.catalog, a.logger)
 
 	// Парсинг событий
 	if a.logger.Enabled(context.Background(), slog.LevelDebug) {
@@ -68,7 +135,7 @@ func (a *App) Run(configPath, eventsPath string, fullOutput bool) (string, error
 	events, err := a.eventParser.ParseEvents(eventsPath)
 	if err != nil {
 		a.logger.Error("Failed to read events", "path", eventsPath, "error", err)
-		return "", err
+		return nil, err
 	}
 
 	// Обработка событий
@@ -77,18 +144,57 @@ func (a *App) Run(configPath, eventsPath string, fullOutput bool) (string, error
 	}
 	for _, event := range events {
 		if err := a.eventProcessor.HandleEvent(event); err != nil {
+			if lenient {
+				a.logger.Warn("Event rejected",
+					"eventTime", event.Time,
+					"competitorID", event.CompetitorID,
+					"error", err,
+				)
+				continue
+			}
 			a.logger.Error("Event processing failed",
 				"eventTime", event.Time,
 				"competitorID", event.CompetitorID,
 				"error", err,
 			)
 			a.logger.Error("Failed to event processing", "error", err)
-			return "", err
+			return nil, err
 		}
 	}
 
-	// Генерация отчёта
-	a.logger.Info("Generating final report")
-	competitors := a.eventProcessor.GetCompetitors()
-	return a.reportGenerator.GenerateReport(competitors, config), nil
+	race := &models.Race{
+		Config:      config,
+		Competitors: a.eventProcessor.GetCompetitors(),
+		Jury:        a.eventProcessor.GetJury(),
+		Registry:    registry,
+	}
+	if registry != nil {
+		a.joinRegistry(race)
+	}
+
+	return race, nil
+}
+
+// joinRegistry проверяет связь участников с реестром (сами данные привязывает EventProcessor)
+// и собирает замечания: события по номерам вне реестра и спортсмены реестра без событий.
+func (a *App) joinRegistry(race *models.Race) {
+	seen := make(map[int]bool, len(race.Competitors))
+	for _, c := range race.Competitors {
+		seen[c.ID] = true
+		if c.Athlete != nil {
+			continue
+		}
+		warning := a.catalog.T("warning.unknownBib", c.ID)
+		a.logger.Warn("Unknown bib", "competitorID", c.ID)
+		race.Warnings = append(race.Warnings, warning)
+	}
+
+	for _, bib := range race.Registry.Bibs() {
+		if seen[bib] {
+			continue
+		}
+		warning := a.catalog.T("warning.noEvents", bib)
+		a.logger.Warn("Registered athlete without events", "bib", bib)
+		race.Warnings = append(race.Warnings, warning)
+	}
 }
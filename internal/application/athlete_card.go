@@ -0,0 +1,127 @@
+package application
+
+import (
+	"errors"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"github.com/BiathlonRaceProto-Yadro/pkg/utils"
+	"sort"
+	"time"
+)
+
+// AthleteCard - карточка участника: строка протокола, итоги и полная хронология гонки
+type AthleteCard struct {
+	Scope    string           `json:"scope,omitempty"` // Протокол, в котором находится участник
+	Result   CompetitorResult `json:"result"`
+	Totals   CardTotals       `json:"totals"`
+	Timeline []CardEntry      `json:"timeline"`
+	Warnings []string         `json:"warnings"`
+}
+
+// CardTotals - итоговые показатели участника
+type CardTotals struct {
+	CompletedLaps   int       `json:"completedLaps"`
+	RaceTime        *Duration `json:"raceTime,omitempty"` // С учётом решений жюри
+	SkiTime         *Duration `json:"skiTime,omitempty"`  // Время гонки без рубежей и штрафных кругов
+	RangeTime       *Duration `json:"rangeTime,omitempty"`
+	ShootingTime    *Duration `json:"shootingTime,omitempty"`
+	PenaltyTime     *Duration `json:"penaltyTime,omitempty"`
+	PenaltyDistance int       `json:"penaltyDistance"` // м
+}
+
+// CardEntry - запись хронологии
+type CardEntry struct {
+	Time string `json:"time"`
+	Kind string `json:"kind"` // event, status, derived или warning
+	Text string `json:"text"`
+}
+
+// RunAthleteCard обрабатывает гонку и строит карточку участника id.
+// Отклонённые события не прерывают обработку: карточка показывает их как замечания.
+func (a *App) RunAthleteCard(configPath, eventsPath, athletesPath string, id int, options ReportOptions) (string, error) {
+	race, err := a.processRace(configPath, eventsPath, athletesPath, true)
+	if err != nil {
+		return "", err
+	}
+
+	card, err := BuildAthleteCard(race, id, options)
+	if err != nil {
+		a.logger.Error("Failed to build athlete card", "competitorID", id, "error", err)
+		return "", err
+	}
+	a.logger.Info("Generating athlete card", "competitorID", id)
+	return NewAthleteCardReportService(options, a.logger).GenerateCard(card), nil
+}
+
+// BuildAthleteCard собирает карточку участника по итогам обработанной гонки.
+func BuildAthleteCard(race *models.Race, id int, options ReportOptions) (*AthleteCard, error) {
+	var c *models.Competitor
+	for _, comp := range race.Competitors {
+		if comp.ID == id {
+			c = comp
+			break
+		}
+	}
+	if c == nil {
+		return nil, errors.New(options.Catalog.T("card.notInRace", id))
+	}
+
+	card := &AthleteCard{Timeline: []CardEntry{}, Warnings: []string{}}
+	result := BuildRaceResult(race, ReportOptions{NetLaps: options.NetLaps, ExcludeRange: options.ExcludeRange, Catalog: options.Catalog})
+	for _, cl := range result.Classifications {
+		for _, res := range cl.Results {
+			if res.ID == id {
+				card.Scope = cl.Scope
+				card.Result = res
+			}
+		}
+	}
+	card.Totals = buildCardTotals(c, race.Config.CourseFor(c.Category))
+
+	entries := append([]models.TimelineEntry(nil), c.Timeline...)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	for _, e := range entries {
+		entry := CardEntry{Time: utils.FormatTimestamp(e.Time), Kind: string(e.Kind), Text: e.Text}
+		if e.Kind == models.TimelineStatus {
+			entry.Text = options.Catalog.T("timeline.status",
+				competitorStatusLabel(options.Catalog, e.From), competitorStatusLabel(options.Catalog, e.To))
+		}
+		card.Timeline = append(card.Timeline, entry)
+		if e.Kind == models.TimelineWarning {
+			card.Warnings = append(card.Warnings, entry.Time+" "+e.Text)
+		}
+	}
+	if race.Registry != nil && c.Athlete == nil {
+		card.Warnings = append(card.Warnings, options.Catalog.T("warning.unknownBib", c.ID))
+	}
+	return card, nil
+}
+
+func buildCardTotals(c *models.Competitor, course *models.Config) CardTotals {
+	totals := CardTotals{CompletedLaps: c.CompletedLaps()}
+
+	missed := c.PenaltyMissedShots()
+	var penalty time.Duration
+	for i, lap := range c.PenaltyLaps() {
+		if lap.Finish.IsZero() {
+			continue
+		}
+		penalty += lap.Finish.Sub(lap.Start)
+		if i < len(missed) {
+			totals.PenaltyDistance += missed[i] * course.PenaltyLen
+		}
+	}
+	if penalty > 0 {
+		totals.PenaltyTime = newDuration(penalty)
+	}
+	if d := c.RangeTime(); d > 0 {
+		totals.RangeTime = newDuration(d)
+	}
+	if d := c.ShootingTime(); d > 0 {
+		totals.ShootingTime = newDuration(d)
+	}
+	if d := c.TotalTime(); d > 0 {
+		totals.RaceTime = newDuration(d)
+		totals.SkiTime = newDuration(d - c.TimeAdjustment - c.RangeTime() - penalty)
+	}
+	return totals
+}
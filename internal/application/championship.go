@@ -0,0 +1,225 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// RaceOutcome - результат обработки одной гонки чемпионата
+type RaceOutcome struct {
+	Entry    models.RaceEntry
+	Race     *models.Race // nil для гонки, загруженной из сохранённого результата
+	Result   *RaceResult  // Результат со всеми протоколами (без фильтра, с общим зачётом)
+	Report   string
+	Err      error
+	Duration time.Duration
+}
+
+// RunChampionship обрабатывает все гонки манифеста параллельно.
+// Ошибка одной гонки не влияет на остальные; итоговый отчёт содержит сводку по всем гонкам.
+// Возвращаемая ошибка не nil, если хотя бы одна гонка завершилась неудачно.
+func (a *App) RunChampionship(manifestPath string, options ReportOptions) (string, error) {
+	if a.logger.Enabled(context.Background(), slog.LevelDebug) {
+		a.logger.Debug("Loading championship manifest", "path", manifestPath)
+	}
+	champ, err := a.manifestLoader.LoadManifest(manifestPath)
+	if err != nil {
+		a.logger.Error("Failed to load manifest", "path", manifestPath, "error", err)
+		return "", err
+	}
+
+	outcomes := a.runRaces(champ.Races, options)
+
+	failed := 0
+	for _, o := range outcomes {
+		if o.Err != nil {
+			failed++
+		}
+	}
+
+	report := a.championshipReport(champ, outcomes)
+	if failed > 0 {
+		return report, fmt.Errorf("%d of %d races failed", failed, len(outcomes))
+	}
+	return report, nil
+}
+
+// runRaces запускает каждую гонку в своей горутине с отдельным экземпляром App.
+func (a *App) runRaces(races []models.RaceEntry, options ReportOptions) []RaceOutcome {
+	outcomes := make([]RaceOutcome, len(races))
+	var wg sync.WaitGroup
+	for i, entry := range races {
+		wg.Add(1)
+		go func(i int, entry models.RaceEntry) {
+			defer wg.Done()
+			outcomes[i] = a.runRace(entry, options)
+		}(i, entry)
+	}
+	wg.Wait()
+	return outcomes
+}
+
+func (a *App) runRace(entry models.RaceEntry, options ReportOptions) (outcome RaceOutcome) {
+	outcome.Entry = entry
+	started := time.Now()
+	logger := a.logger.With("race", entry.Name)
+
+	// Паника при обработке одной гонки не должна останавливать остальные
+	defer func() {
+		if rec := recover(); rec != nil {
+			outcome.Err = fmt.Errorf("race processing panicked: %v", rec)
+			logger.Error("Race failed", "error", outcome.Err)
+		}
+		outcome.Duration = time.Since(started)
+	}()
+
+	if entry.Results != "" {
+		outcome.Result, outcome.Err = a.resultLoader.LoadResult(entry.Results)
+		if outcome.Err == nil && outcome.Result.Race.Format != "" {
+			outcome.Err = checkRaceFormat(entry, outcome.Result.Race.Format)
+		}
+		if outcome.Err != nil {
+			logger.Error("Race failed", "error", outcome.Err)
+			return outcome
+		}
+		generator := NewReportGenerator(nil, options, logger)
+		outcome.Report, outcome.Err = generator.(ResultRenderer).RenderResult(scopeResult(outcome.Result, entry.Category))
+		if outcome.Err != nil {
+			logger.Error("Race failed", "error", outcome.Err)
+		}
+		return outcome
+	}
+
+	// Категория гонки из манифеста ограничивает протокол, если фильтр не задан явно
+	if entry.Category != "" && options.Filter == nil {
+		options.Filter = &ResultFilter{Field: "category", Value: entry.Category}
+	}
+
+	app := NewApp(a.configLoader, a.registryLoader, a.manifestLoader, a.resultLoader, a.eventParser, nil, nil, a.catalog, logger)
+	outcome.Race, outcome.Err = app.processRace(entry.Config, entry.Events, entry.Athletes, false)
+	if outcome.Err == nil {
+		outcome.Err = checkRaceFormat(entry, outcome.Race.Config.Format)
+	}
+	if outcome.Err != nil {
+		logger.Error("Race failed", "error", outcome.Err)
+		return outcome
+	}
+	// Графики каждой гонки сохраняются в собственный подкаталог
+	if options.ChartsDir != "" {
+		dir := filepath.Join(options.ChartsDir, chartFileName(entry.Name))
+		if outcome.Err = WriteCharts(dir, RaceCharts(outcome.Race, options)); outcome.Err != nil {
+			logger.Error("Race failed", "error", outcome.Err)
+			return outcome
+		}
+	}
+	outcome.Result = BuildRaceResult(outcome.Race, ReportOptions{Overall: true})
+	outcome.Report, outcome.Err = NewReportGenerator(outcome.Race.Config, options, logger).GenerateReport(outcome.Race)
+	if outcome.Err != nil {
+		logger.Error("Race failed", "error", outcome.Err)
+	}
+	return outcome
+}
+
+// checkRaceFormat сверяет формат гонки из манифеста с форматом её конфигурации
+// или сохранённого результата, чтобы гонка не попала в чужой зачёт по дисциплине
+func checkRaceFormat(entry models.RaceEntry, actual string) error {
+	if entry.Format == "" || strings.EqualFold(entry.Format, actual) {
+		return nil
+	}
+	return fmt.Errorf("manifest format %q does not match race format %q", entry.Format, actual)
+}
+
+// scopeResult оставляет в загруженном результате протокол категории гонки, если он есть
+func scopeResult(result *RaceResult, category string) *RaceResult {
+	if category == "" {
+		return result
+	}
+	scoped := *result
+	scoped.Classifications = nil
+	for _, cl := range result.Classifications {
+		if strings.EqualFold(cl.Scope, category) {
+			scoped.Classifications = append(scoped.Classifications, cl)
+		}
+	}
+	if len(scoped.Classifications) == 0 {
+		return result
+	}
+	return &scoped
+}
+
+// Общий отчёт: протоколы успешных гонок и сводка
+func (a *App) championshipReport(champ *models.Championship, outcomes []RaceOutcome) string {
+	var sb strings.Builder
+	if champ.Name != "" {
+		sb.WriteString(champ.Name + "\n\n")
+	}
+
+	for _, o := range outcomes {
+		if o.Err != nil {
+			continue
+		}
+		sb.WriteString("=== " + raceTitle(o.Entry) + " ===\n")
+		sb.WriteString(o.Report)
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(a.catalog.T("report.championship") + ":\n")
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	header, separator := tableHeader(a.catalog, "col.race", "col.format", "col.category", "col.result", "col.time", "col.error")
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		a.logger.Error("failed to write header", "error", err)
+	}
+	if _, err := fmt.Fprintln(w, separator); err != nil {
+		a.logger.Error("failed to write separator", "error", err)
+	}
+	for _, o := range outcomes {
+		result, errText := a.catalog.T("label.ok"), "-"
+		if o.Err != nil {
+			result, errText = a.catalog.T("label.failed"), o.Err.Error()
+		}
+		row := fmt.Sprintf(
+			"%s\t%s\t%s\t%s\t%dms\t%s",
+			o.Entry.Name,
+			orDash(o.Entry.Format),
+			orDash(o.Entry.Category),
+			result,
+			o.Duration.Milliseconds(),
+			errText,
+		)
+		if _, err := fmt.Fprintln(w, row); err != nil {
+			a.logger.Error("failed to write row", "error", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		a.logger.Error("failed to flush tabwriter", "error", err)
+	}
+	return sb.String()
+}
+
+func raceTitle(entry models.RaceEntry) string {
+	var details []string
+	if entry.Format != "" {
+		details = append(details, entry.Format)
+	}
+	if entry.Category != "" {
+		details = append(details, entry.Category)
+	}
+	if len(details) == 0 {
+		return entry.Name
+	}
+	return fmt.Sprintf("%s (%s)", entry.Name, strings.Join(details, ", "))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
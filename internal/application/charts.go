@@ -0,0 +1,391 @@
+package application
+
+import (
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"github.com/BiathlonRaceProto-Yadro/internal/i18n"
+	"github.com/BiathlonRaceProto-Yadro/pkg/utils"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Chart - готовый график в формате SVG
+type Chart struct {
+	Name  string // Имя файла без расширения
+	Title string
+	SVG   string
+}
+
+// ScopeCharts - графики одного протокола (вся гонка, категория или общий зачёт)
+type ScopeCharts struct {
+	Scope  string
+	Charts []Chart
+}
+
+// Размеры графиков
+const (
+	chartWidth       = 900
+	chartPlotLeft    = 70
+	chartPlotTop     = 40
+	chartLegendWidth = 200
+	chartPlotBottom  = 50
+)
+
+// RaceCharts строит графики по каждому протоколу гонки с учётом фильтра.
+// Отставание считается от лидера всего протокола, а не только отфильтрованных участников.
+func RaceCharts(race *models.Race, options ReportOptions) []ScopeCharts {
+	var result []ScopeCharts
+	for _, scope := range raceScopes(race, options.Overall) {
+		ranked := RankCompetitors(scope.members)
+		competitors := rowCompetitors(options.Filter.Apply(ranked))
+		result = append(result, ScopeCharts{
+			Scope:  scope.name,
+			Charts: BuildCharts(rowCompetitors(ranked), competitors, race.Config, options.Catalog),
+		})
+	}
+	return result
+}
+
+// BuildCharts строит графики по участникам в порядке протокола:
+// отставание от лидера, время кругов, результаты стрельбы и скорость на штрафных кругах.
+// field - все участники протокола, по ним определяется лидер в каждой точке дистанции.
+// Дистанция каждого участника берётся из трассы его категории (config.CourseFor).
+// Подписи берутся из каталога сообщений.
+func BuildCharts(field, competitors []*models.Competitor, config *models.Config, catalog *i18n.Catalog) []Chart {
+	return []Chart{
+		gapChart(field, competitors, config, catalog),
+		lapTimesChart(competitors, catalog),
+		shootingChart(competitors, catalog),
+		penaltySpeedChart(competitors, config, catalog),
+	}
+}
+
+// WriteCharts сохраняет графики в каталог dir отдельными .svg-файлами.
+// Для протоколов категорий к имени файла добавляется имя категории.
+func WriteCharts(dir string, charts []ScopeCharts) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, sc := range charts {
+		for _, chart := range sc.Charts {
+			name := chart.Name
+			if sc.Scope != "" {
+				name = chartFileName(sc.Scope) + "-" + name
+			}
+			if err := os.WriteFile(filepath.Join(dir, name+".svg"), []byte(chart.SVG), 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// chartFileName оставляет в имени протокола только буквы, цифры и дефисы
+func chartFileName(scope string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(scope) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r > 127:
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('-')
+		}
+	}
+	return sb.String()
+}
+
+// chartLabel - подпись участника на графике
+func chartLabel(c *models.Competitor) string {
+	if c.Athlete != nil && c.Athlete.Name != "" {
+		return fmt.Sprintf("%d %s", c.ID, c.Athlete.Name)
+	}
+	return fmt.Sprint(c.ID)
+}
+
+// noDataChart - заглушка для графика без данных
+func noDataChart(name, title string, catalog *i18n.Catalog) Chart {
+	canvas := newSVGCanvas(chartWidth, 120, title)
+	canvas.text(chartWidth/2, 70, "middle", "", catalog.T("chart.noData"))
+	return Chart{Name: name, Title: title, SVG: canvas.String()}
+}
+
+// gapPoint - время гонки участника в точке дистанции
+type gapPoint struct {
+	distance int
+	elapsed  time.Duration
+}
+
+// gapPoints - время гонки участника на отсечках и окончаниях кругов; nil, если он не стартовал
+func gapPoints(c *models.Competitor, course *models.Config) []gapPoint {
+	if c.ActualStart.IsZero() {
+		return nil
+	}
+	points := []gapPoint{{distance: 0}}
+	for _, st := range c.Splits {
+		if sp, ok := course.FindSplit(st.SplitID); ok {
+			points = append(points, gapPoint{
+				distance: (st.Lap-1)*course.LapLen + sp.Distance,
+				elapsed:  c.Elapsed(st.Time),
+			})
+		}
+	}
+	for _, cp := range c.Checkpoints {
+		if cp.Kind == models.LapEnd {
+			points = append(points, gapPoint{distance: cp.Index * course.LapLen, elapsed: c.Elapsed(cp.Time)})
+		}
+	}
+	sort.SliceStable(points, func(a, b int) bool { return points[a].distance < points[b].distance })
+	return points
+}
+
+// Отставание от лидера по ходу дистанции: отсечки и окончания кругов.
+// Лидером в каждой точке считается участник field с лучшим временем гонки.
+// Шкала дистанции строится по самой длинной трассе среди участников field.
+func gapChart(field, competitors []*models.Competitor, config *models.Config, catalog *i18n.Catalog) Chart {
+	const name = "gap-to-leader"
+	title := catalog.T("chart.gap")
+
+	course := config
+	if len(field) > 0 {
+		course = config.CourseFor(field[0].Category)
+	}
+	best := make(map[int]time.Duration)
+	for _, c := range field {
+		if cc := config.CourseFor(c.Category); cc.Laps*cc.LapLen > course.Laps*course.LapLen {
+			course = cc
+		}
+		for _, p := range gapPoints(c, config.CourseFor(c.Category)) {
+			if cur, ok := best[p.distance]; !ok || p.elapsed < cur {
+				best[p.distance] = p.elapsed
+			}
+		}
+	}
+	series := make([][]gapPoint, len(competitors))
+	for i, c := range competitors {
+		series[i] = gapPoints(c, config.CourseFor(c.Category))
+	}
+
+	var maxGap time.Duration
+	var hasData bool
+	for _, points := range series {
+		for _, p := range points {
+			hasData = hasData || p.distance > 0
+			if gap := p.elapsed - best[p.distance]; gap > maxGap {
+				maxGap = gap
+			}
+		}
+	}
+	if !hasData {
+		return noDataChart(name, title, catalog)
+	}
+
+	// Высота растёт вместе с легендой
+	height := max(420, chartPlotTop+18*len(competitors)+chartPlotBottom)
+	area := plotArea{left: chartPlotLeft, top: chartPlotTop,
+		width: chartWidth - chartPlotLeft - chartLegendWidth, height: float64(height - chartPlotTop - chartPlotBottom)}
+	canvas := newSVGCanvas(chartWidth, height, title)
+
+	_, hi, step := axisRange(0, maxGap.Seconds(), 6)
+	scaleY := canvas.yAxis(area, 0, hi, step, catalog.T("chart.gapAxis"), formatSeconds)
+
+	total := float64(course.Laps * course.LapLen)
+	if total <= 0 {
+		total = 1
+	}
+	scaleX := func(d int) float64 { return area.left + float64(d)/total*area.width }
+	canvas.line(area.left, area.bottom(), area.right(), area.bottom(), "#333333", 1)
+	for lap := 0; lap <= course.Laps; lap++ {
+		x := scaleX(lap * course.LapLen)
+		canvas.line(x, area.bottom(), x, area.bottom()+4, "#333333", 1)
+		canvas.text(x, area.bottom()+18, "middle", "", fmt.Sprintf("%.1f", float64(lap*course.LapLen)/1000))
+	}
+	canvas.text(area.left+area.width/2, area.bottom()+38, "middle", "", catalog.T("chart.distanceAxis"))
+
+	var labels []string
+	for i, points := range series {
+		labels = append(labels, chartLabel(competitors[i]))
+		if len(points) == 0 {
+			continue
+		}
+		color := seriesColor(i)
+		coords := make([][2]float64, len(points))
+		for j, p := range points {
+			gap := p.elapsed - best[p.distance]
+			coords[j] = [2]float64{scaleX(p.distance), scaleY(gap.Seconds())}
+		}
+		canvas.polyline(coords, color)
+		for j, p := range points[1:] {
+			gap := p.elapsed - best[p.distance]
+			tooltip := catalog.T("chart.gapTooltip", labels[i], p.distance,
+				utils.FormatDuration(p.elapsed), utils.FormatDuration(gap))
+			canvas.circle(coords[j+1][0], coords[j+1][1], 3, color, color, tooltip)
+		}
+	}
+	canvas.legend(area, labels)
+	return Chart{Name: name, Title: title, SVG: canvas.String()}
+}
+
+// Время основных кругов: группа столбцов на участника, цвет - номер круга.
+func lapTimesChart(competitors []*models.Competitor, catalog *i18n.Catalog) Chart {
+	const name = "lap-times"
+	title := catalog.T("chart.lapTimes")
+
+	var maxLap time.Duration
+	var lapCount int
+	for _, c := range competitors {
+		laps := c.MainLaps()
+		lapCount = max(lapCount, len(laps))
+		for _, lap := range laps {
+			if !lap.Finish.IsZero() {
+				maxLap = max(maxLap, lap.Finish.Sub(lap.Start))
+			}
+		}
+	}
+	if maxLap == 0 {
+		return noDataChart(name, title, catalog)
+	}
+
+	height := 420
+	area := plotArea{left: chartPlotLeft, top: chartPlotTop,
+		width: chartWidth - chartPlotLeft - chartLegendWidth, height: float64(height - chartPlotTop - chartPlotBottom)}
+	canvas := newSVGCanvas(chartWidth, height, title)
+	_, hi, step := axisRange(0, maxLap.Seconds(), 6)
+	scaleY := canvas.yAxis(area, 0, hi, step, catalog.T("chart.lapTimeAxis"), formatSeconds)
+	canvas.line(area.left, area.bottom(), area.right(), area.bottom(), "#333333", 1)
+
+	group := area.width / float64(len(competitors))
+	bar := group * 0.8 / float64(lapCount)
+	for i, c := range competitors {
+		x0 := area.left + float64(i)*group + group*0.1
+		for j, lap := range c.MainLaps() {
+			if lap.Finish.IsZero() {
+				continue
+			}
+			d := lap.Finish.Sub(lap.Start)
+			y := scaleY(d.Seconds())
+			tooltip := catalog.T("chart.lapTooltip", chartLabel(c), j+1, utils.FormatDuration(d))
+			canvas.rect(x0+float64(j)*bar, y, bar-1, area.bottom()-y, seriesColor(j), tooltip)
+		}
+		canvas.text(area.left+float64(i)*group+group/2, area.bottom()+18, "middle", "", fmt.Sprint(c.ID))
+	}
+	canvas.text(area.left+area.width/2, area.bottom()+38, "middle", "", catalog.T("chart.competitorAxis"))
+
+	labels := make([]string, lapCount)
+	for j := range labels {
+		labels[j] = catalog.T("chart.lap", j+1)
+	}
+	canvas.legend(area, labels)
+	return Chart{Name: name, Title: title, SVG: canvas.String()}
+}
+
+// Сетка попаданий: строка - участник, столбец - огневой рубеж, кружок - мишень
+// (закрашен - поражена, пустой - промах).
+func shootingChart(competitors []*models.Competitor, catalog *i18n.Catalog) Chart {
+	const name = "shooting"
+	title := catalog.T("chart.shooting")
+
+	var stages, targets int
+	for _, c := range competitors {
+		stages = max(stages, len(c.FiringLines))
+		for _, s := range c.FiringLines {
+			targets = max(targets, s.Targets())
+		}
+	}
+	if stages == 0 {
+		return noDataChart(name, title, catalog)
+	}
+
+	const rowHeight, labelWidth, radius = 24.0, 180.0, 6.0
+	cellWidth := float64(targets)*radius*2.6 + 20
+	height := int(chartPlotTop + 30 + rowHeight*float64(len(competitors)) + 20)
+	canvas := newSVGCanvas(chartWidth, height, title)
+
+	top := float64(chartPlotTop) + 20
+	for stage := 0; stage < stages; stage++ {
+		canvas.text(labelWidth+cellWidth*(float64(stage)+0.5), top-4, "middle", "bold", catalog.T("chart.stage", stage+1))
+	}
+	for i, c := range competitors {
+		y := top + rowHeight*float64(i)
+		if i%2 == 0 {
+			canvas.rect(0, y, chartWidth, rowHeight, "#f6f6f6", "")
+		}
+		canvas.text(10, y+rowHeight/2+4, "start", "", chartLabel(c))
+		for stage, s := range c.FiringLines {
+			x0 := labelWidth + cellWidth*float64(stage) + 10
+			for t := 1; t <= s.Targets(); t++ {
+				cx := x0 + radius + float64(t-1)*radius*2.6
+				cy := y + rowHeight/2
+				if s.Hit(t) {
+					tooltip := catalog.T("chart.targetTooltip", chartLabel(c), stage+1, t, catalog.T("chart.hit"))
+					canvas.circle(cx, cy, radius, "#2ca02c", "#2ca02c", tooltip)
+				} else {
+					tooltip := catalog.T("chart.targetTooltip", chartLabel(c), stage+1, t, catalog.T("chart.miss"))
+					canvas.circle(cx, cy, radius, "#ffffff", "#d62728", tooltip)
+				}
+			}
+		}
+	}
+	return Chart{Name: name, Title: title, SVG: canvas.String()}
+}
+
+// Средняя скорость на штрафных кругах по участникам (м/с).
+// Длина штрафного круга берётся из трассы категории участника.
+func penaltySpeedChart(competitors []*models.Competitor, config *models.Config, catalog *i18n.Catalog) Chart {
+	const name = "penalty-speed"
+	title := catalog.T("chart.penaltySpeed")
+
+	type entry struct {
+		competitor *models.Competitor
+		speed      float64
+		loops      int
+	}
+	var entries []entry
+	var maxSpeed float64
+	for _, c := range competitors {
+		course := config.CourseFor(c.Category)
+		missed := c.PenaltyMissedShots()
+		var distance int
+		var duration time.Duration
+		for i, lap := range c.PenaltyLaps() {
+			if lap.Finish.IsZero() || i >= len(missed) {
+				continue
+			}
+			distance += missed[i] * course.PenaltyLen
+			duration += lap.Finish.Sub(lap.Start)
+		}
+		if v := speed(distance, duration); v > 0 {
+			entries = append(entries, entry{competitor: c, speed: v, loops: distance / max(course.PenaltyLen, 1)})
+			maxSpeed = max(maxSpeed, v)
+		}
+	}
+	if len(entries) == 0 {
+		return noDataChart(name, title, catalog)
+	}
+
+	const rowHeight, labelWidth = 24.0, 180.0
+	height := int(chartPlotTop + rowHeight*float64(len(entries)) + chartPlotBottom)
+	canvas := newSVGCanvas(chartWidth, height, title)
+	_, hi, step := axisRange(0, maxSpeed, 6)
+	width := chartWidth - labelWidth - 40
+	scaleX := func(v float64) float64 { return labelWidth + v/hi*width }
+
+	bottom := float64(chartPlotTop) + rowHeight*float64(len(entries))
+	for v := 0.0; v <= hi+step/2; v += step {
+		x := scaleX(v)
+		canvas.line(x, chartPlotTop, x, bottom, "#e5e5e5", 1)
+		canvas.text(x, bottom+16, "middle", "", fmt.Sprintf("%.1f", v))
+	}
+	canvas.text(labelWidth+width/2, bottom+36, "middle", "", catalog.T("chart.speedAxis"))
+
+	for i, e := range entries {
+		y := float64(chartPlotTop) + rowHeight*float64(i)
+		canvas.text(labelWidth-8, y+rowHeight/2+4, "end", "", chartLabel(e.competitor))
+		tooltip := catalog.T("chart.penaltyTooltip", chartLabel(e.competitor), e.speed, e.loops)
+		canvas.rect(labelWidth, y+4, scaleX(e.speed)-labelWidth, rowHeight-8, seriesColor(i), tooltip)
+	}
+	canvas.line(labelWidth, chartPlotTop, labelWidth, bottom, "#333333", 1)
+	return Chart{Name: name, Title: title, SVG: canvas.String()}
+}
@@ -0,0 +1,274 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// ResultDiff - различия двух результатов гонки (до и после исправления событий или конфигурации)
+type ResultDiff struct {
+	Tolerance       *Duration            `json:"tolerance"`
+	Exceeded        bool                 `json:"exceeded"` // Есть различия сверх допуска
+	Classifications []ClassificationDiff `json:"classifications"`
+}
+
+// ClassificationDiff - различия одного протокола
+type ClassificationDiff struct {
+	Scope       string           `json:"scope,omitempty"`
+	Added       []int            `json:"added,omitempty"`   // Участники только во втором результате
+	Removed     []int            `json:"removed,omitempty"` // Участники только в первом результате
+	Competitors []CompetitorDiff `json:"competitors"`
+}
+
+// CompetitorDiff - изменения результата участника; неизменившиеся показатели не заполняются
+type CompetitorDiff struct {
+	ID        int           `json:"id"`
+	Name      string        `json:"name,omitempty"`
+	Rank      *RankChange   `json:"rank,omitempty"`
+	Status    *StatusChange `json:"status,omitempty"`
+	TotalTime *TimeChange   `json:"totalTime,omitempty"`
+	Laps      []LapChange   `json:"laps,omitempty"`
+	Shooting  []StageChange `json:"shooting,omitempty"`
+}
+
+// RankChange - изменение места; 0 - без места
+type RankChange struct {
+	Before int `json:"before"`
+	After  int `json:"after"`
+}
+
+// StatusChange - изменение кода статуса
+type StatusChange struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// TimeChange - изменение времени; Delta заполняется, если время есть в обоих результатах
+type TimeChange struct {
+	Before          *Duration `json:"before,omitempty"`
+	After           *Duration `json:"after,omitempty"`
+	Delta           *Duration `json:"delta,omitempty"`
+	WithinTolerance bool      `json:"withinTolerance"`
+}
+
+// LapChange - изменение времени основного круга
+type LapChange struct {
+	Lap int `json:"lap"`
+	TimeChange
+}
+
+// StageChange - изменение результата стрельбы на рубеже
+type StageChange struct {
+	Stage        int `json:"stage"`
+	HitsBefore   int `json:"hitsBefore"`
+	HitsAfter    int `json:"hitsAfter"`
+	MissedBefore int `json:"missedBefore"`
+	MissedAfter  int `json:"missedAfter"`
+}
+
+// RunDiff обрабатывает два файла событий с одной конфигурацией и сравнивает результаты.
+// Второе значение сообщает, превышают ли различия допуск.
+func (a *App) RunDiff(configPath, eventsBefore, eventsAfter, athletesPath string, options ReportOptions) (string, bool, error) {
+	var results [2]*RaceResult
+	for i, path := range []string{eventsBefore, eventsAfter} {
+		race, err := a.processRace(configPath, path, athletesPath, false)
+		if err != nil {
+			return "", false, err
+		}
+		results[i] = BuildRaceResult(race, options)
+	}
+	return a.renderDiff(results[0], results[1], options)
+}
+
+// RunResultDiff сравнивает два сохранённых результата (JSON или XML).
+func (a *App) RunResultDiff(before, after string, options ReportOptions) (string, bool, error) {
+	var results [2]*RaceResult
+	for i, path := range []string{before, after} {
+		if a.logger.Enabled(context.Background(), slog.LevelDebug) {
+			a.logger.Debug("Loading results", "path", path)
+		}
+		result, err := a.resultLoader.LoadResult(path)
+		if err != nil {
+			a.logger.Error("Failed to load results", "path", path, "error", err)
+			return "", false, err
+		}
+		results[i] = result
+	}
+	return a.renderDiff(results[0], results[1], options)
+}
+
+func (a *App) renderDiff(before, after *RaceResult, options ReportOptions) (string, bool, error) {
+	diff := DiffResults(before, after, options.Tolerance)
+	a.logger.Info("Generating results diff", "exceeded", diff.Exceeded)
+	return NewDiffReportService(options, a.logger).GenerateDiff(diff), diff.Exceeded, nil
+}
+
+// DiffResults сравнивает протоколы с одинаковым зачётом, участников - по номеру.
+// Различия времени в пределах tolerance выводятся, но не считаются превышением.
+func DiffResults(before, after *RaceResult, tolerance time.Duration) *ResultDiff {
+	diff := &ResultDiff{Tolerance: newDuration(tolerance), Classifications: []ClassificationDiff{}}
+
+	scopes := make(map[string]*Classification)
+	for i := range before.Classifications {
+		scopes[before.Classifications[i].Scope] = &before.Classifications[i]
+	}
+	seen := make(map[string]bool)
+	for _, cl := range after.Classifications {
+		seen[cl.Scope] = true
+		prev := scopes[cl.Scope]
+		if prev == nil {
+			prev = &Classification{Scope: cl.Scope}
+		}
+		diff.addClassification(diffClassification(*prev, cl, tolerance))
+	}
+	for _, cl := range before.Classifications {
+		if !seen[cl.Scope] {
+			diff.addClassification(diffClassification(cl, Classification{Scope: cl.Scope}, tolerance))
+		}
+	}
+	return diff
+}
+
+func (d *ResultDiff) addClassification(cd ClassificationDiff) {
+	if len(cd.Added) == 0 && len(cd.Removed) == 0 && len(cd.Competitors) == 0 {
+		return
+	}
+	if len(cd.Added) > 0 || len(cd.Removed) > 0 {
+		d.Exceeded = true
+	}
+	for _, c := range cd.Competitors {
+		if c.exceeds() {
+			d.Exceeded = true
+		}
+	}
+	d.Classifications = append(d.Classifications, cd)
+}
+
+func diffClassification(before, after Classification, tolerance time.Duration) ClassificationDiff {
+	cd := ClassificationDiff{Scope: after.Scope, Competitors: []CompetitorDiff{}}
+
+	prev := make(map[int]CompetitorResult, len(before.Results))
+	for _, res := range before.Results {
+		prev[res.ID] = res
+	}
+	for _, res := range after.Results {
+		old, ok := prev[res.ID]
+		if !ok {
+			cd.Added = append(cd.Added, res.ID)
+			continue
+		}
+		delete(prev, res.ID)
+		if c, changed := diffCompetitor(old, res, tolerance); changed {
+			cd.Competitors = append(cd.Competitors, c)
+		}
+	}
+	for id := range prev {
+		cd.Removed = append(cd.Removed, id)
+	}
+	sort.Ints(cd.Removed)
+	return cd
+}
+
+func diffCompetitor(before, after CompetitorResult, tolerance time.Duration) (CompetitorDiff, bool) {
+	c := CompetitorDiff{ID: after.ID}
+	if after.Athlete != nil {
+		c.Name = after.Athlete.Name
+	} else if before.Athlete != nil {
+		c.Name = before.Athlete.Name
+	}
+
+	changed := false
+	if before.Rank != after.Rank {
+		c.Rank = &RankChange{Before: before.Rank, After: after.Rank}
+		changed = true
+	}
+	if before.StatusCode != after.StatusCode {
+		c.Status = &StatusChange{Before: before.StatusCode, After: after.StatusCode}
+		changed = true
+	}
+	if tc := diffTime(before.TotalTime, after.TotalTime, tolerance); tc != nil {
+		c.TotalTime = tc
+		changed = true
+	}
+
+	laps := make(map[int]*Duration)
+	maxLap := 0
+	for _, lap := range before.Laps {
+		laps[lap.Lap] = lap.Duration
+		maxLap = max(maxLap, lap.Lap)
+	}
+	afterLaps := make(map[int]*Duration)
+	for _, lap := range after.Laps {
+		afterLaps[lap.Lap] = lap.Duration
+		maxLap = max(maxLap, lap.Lap)
+	}
+	for lap := 1; lap <= maxLap; lap++ {
+		if tc := diffTime(laps[lap], afterLaps[lap], tolerance); tc != nil {
+			c.Laps = append(c.Laps, LapChange{Lap: lap, TimeChange: *tc})
+			changed = true
+		}
+	}
+
+	stages := make(map[int]ShootingStage)
+	maxStage := 0
+	for _, st := range before.Shooting {
+		stages[st.Stage] = st
+		maxStage = max(maxStage, st.Stage)
+	}
+	afterStages := make(map[int]ShootingStage)
+	for _, st := range after.Shooting {
+		afterStages[st.Stage] = st
+		maxStage = max(maxStage, st.Stage)
+	}
+	for stage := 1; stage <= maxStage; stage++ {
+		b, a := stages[stage], afterStages[stage]
+		if b.Hits != a.Hits || b.Missed != a.Missed || b.Targets != a.Targets {
+			c.Shooting = append(c.Shooting, StageChange{
+				Stage:        stage,
+				HitsBefore:   b.Hits,
+				HitsAfter:    a.Hits,
+				MissedBefore: b.Missed,
+				MissedAfter:  a.Missed,
+			})
+			changed = true
+		}
+	}
+	return c, changed
+}
+
+// diffTime возвращает nil, если время не изменилось
+func diffTime(before, after *Duration, tolerance time.Duration) *TimeChange {
+	switch {
+	case before == nil && after == nil:
+		return nil
+	case before == nil || after == nil:
+		return &TimeChange{Before: before, After: after}
+	case before.Ms == after.Ms:
+		return nil
+	}
+	delta := time.Duration(after.Ms-before.Ms) * time.Millisecond
+	return &TimeChange{
+		Before:          before,
+		After:           after,
+		Delta:           newDuration(delta),
+		WithinTolerance: delta.Abs() <= tolerance,
+	}
+}
+
+// exceeds сообщает, есть ли у участника изменения сверх допуска
+func (c CompetitorDiff) exceeds() bool {
+	if c.Rank != nil || c.Status != nil || len(c.Shooting) > 0 {
+		return true
+	}
+	if c.TotalTime != nil && !c.TotalTime.WithinTolerance {
+		return true
+	}
+	for _, lap := range c.Laps {
+		if !lap.WithinTolerance {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package application
+
+import (
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"strings"
+)
+
+// ResultFilter отбирает строки протокола по полю реестра спортсменов (nation=NOR, category=Junior)
+type ResultFilter struct {
+	Field string
+	Value string
+}
+
+func ParseResultFilter(raw string) (*ResultFilter, error) {
+	field, value, ok := strings.Cut(raw, "=")
+	if !ok || field == "" {
+		return nil, fmt.Errorf("filter must look like field=value")
+	}
+	if _, known := (&models.Athlete{}).Field(field); !known {
+		return nil, fmt.Errorf("unknown filter field %q", field)
+	}
+	return &ResultFilter{Field: field, Value: value}, nil
+}
+
+// Match проверяет участника. Без данных реестра доступен только фильтр по номеру.
+func (f *ResultFilter) Match(c *models.Competitor) bool {
+	athlete := c.Athlete
+	if athlete == nil {
+		athlete = &models.Athlete{Bib: c.ID}
+	}
+	value, _ := athlete.Field(f.Field)
+	// Категория могла быть назначена по диапазону номеров, а не из реестра
+	if strings.EqualFold(f.Field, "category") && c.Category != "" {
+		value = c.Category
+	}
+	return strings.EqualFold(value, f.Value)
+}
+
+// Apply оставляет подходящие строки, сохраняя места из общего протокола.
+func (f *ResultFilter) Apply(rows []ResultRow) []ResultRow {
+	if f == nil {
+		return rows
+	}
+	var filtered []ResultRow
+	for _, row := range rows {
+		if f.Match(row.Competitor) {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,63 @@
+package application
+
+import (
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"github.com/BiathlonRaceProto-Yadro/internal/i18n"
+	"strings"
+	"unicode/utf8"
+)
+
+// statusLabel - подпись статуса: из конфигурации гонки (statusLabels), иначе из каталога сообщений
+func statusLabel(cfg *models.Config, catalog *i18n.Catalog, code models.StatusCode) string {
+	if cfg != nil {
+		if label, ok := cfg.StatusLabels[code]; ok {
+			return label
+		}
+	}
+	if label, ok := catalog.Lookup("status." + string(code)); ok {
+		return label
+	}
+	return string(code)
+}
+
+// competitorStatusLabel - название статуса участника в гонке (Racing, InPenalty, ...) на языке каталога
+func competitorStatusLabel(catalog *i18n.Catalog, status models.CompetitorStatus) string {
+	if label, ok := catalog.Lookup("state." + status.String()); ok {
+		return label
+	}
+	return status.String()
+}
+
+// scopeTitle - название протокола для вывода; Scope в результате остаётся неизменным идентификатором
+func scopeTitle(catalog *i18n.Catalog, scope string) string {
+	if title, ok := catalog.Lookup("scope." + scope); ok {
+		return title
+	}
+	return scope
+}
+
+// positionLabel - название положения для стрельбы (Prone, Standing) на языке каталога
+func positionLabel(catalog *i18n.Catalog, position string) string {
+	if label, ok := catalog.Lookup("position." + position); ok {
+		return label
+	}
+	return position
+}
+
+// tableHeader переводит заголовки колонок и строит под ними подчёркивание той же ширины
+func tableHeader(catalog *i18n.Catalog, keys ...string) (header, separator string) {
+	columns := make([]string, len(keys))
+	for i, key := range keys {
+		columns[i] = catalog.T(key)
+	}
+	return strings.Join(columns, "\t"), underline(columns...)
+}
+
+// underline - строка из "-" под каждой колонкой
+func underline(columns ...string) string {
+	dashes := make([]string, len(columns))
+	for i, col := range columns {
+		dashes[i] = strings.Repeat("-", utf8.RuneCountInString(col))
+	}
+	return strings.Join(dashes, "\t")
+}
@@ -3,35 +3,67 @@ package application
 import (
 	"context"
 	"errors"
-	"fmt"
 	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"github.com/BiathlonRaceProto-Yadro/internal/i18n"
 	"github.com/BiathlonRaceProto-Yadro/pkg/utils"
 	"log/slog"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type EventProcessor struct {
 	competitors map[int]*models.Competitor
+	jury        *models.Jury
 	config      *models.Config
+	registry    *models.Registry
+	catalog     *i18n.Catalog
 	logger      *slog.Logger
 }
 
-func NewEventProcessor(cfg *models.Config, lg *slog.Logger) *EventProcessor {
+// NewEventProcessor создаёт обработчик событий. registry и catalog могут быть nil
+// (каталог nil - сообщения на языке по умолчанию).
+func NewEventProcessor(cfg *models.Config, registry *models.Registry, catalog *i18n.Catalog, lg *slog.Logger) *EventProcessor {
 	return &EventProcessor{
 		competitors: make(map[int]*models.Competitor),
+		jury:        models.NewJury(),
 		config:      cfg,
+		registry:    registry,
+		catalog:     catalog,
 		logger:      lg,
 	}
 }
 
 func (p *EventProcessor) HandleEvent(event models.Event) error {
+	// Решения по всей гонке не относятся к конкретному участнику
+	if event.Type == models.JuryResultsState {
+		return p.handlerResultsState(event)
+	}
+
+	// Решения жюри относятся только к участникам, которые уже есть в гонке
+	if _, exists := p.competitors[event.CompetitorID]; !exists && isJuryEvent(event.Type) {
+		return errors.New(p.catalog.T("error.juryUnknownCompetitor", event.CompetitorID))
+	}
+
 	c := p.getOrCreate(event.CompetitorID)
+	c.Note(event.Time, models.TimelineEvent, p.describeEvent(event))
 
 	if err := p.validateOrder(event, c); err != nil {
+		c.Note(event.Time, models.TimelineWarning, p.catalog.T("warning.rejected", err))
 		return err
 	}
 
+	// Снятый с трассы участник больше не участвует в гонке, учитываются только решения жюри
+	if c.Status == models.Lapped && !isJuryEvent(event.Type) {
+		c.Note(event.Time, models.TimelineWarning, p.catalog.T("warning.lappedIgnored"))
+		p.logger.Warn(p.catalog.T("log.lappedIgnored"),
+			"eventType", event.Type,
+			"eventTime", utils.FormatTimestamp(event.Time),
+			"competitorID", c.ID)
+		return nil
+	}
+
 	handlers := map[models.EventType]func(*models.Competitor, models.Event) error{
 		models.CompetitorRegistered: p.handlerRegister,
 		models.StartTimeSet:         p.handlerSetStartTime,
@@ -44,12 +76,24 @@ func (p *EventProcessor) HandleEvent(event models.Event) error {
 		models.LeftPenalty:          p.handlerLeavePenalty,
 		models.LapFinished:          p.handlerFinishLap,
 		models.CannotContinue:       p.handlerCannotContinue,
+		models.ShotFired:            p.handlerShotFired,
+		models.PassedSplit:          p.handlerPassSplit,
+		models.JuryDisqualified:     p.handlerJuryDisqualify,
+		models.JuryTimeAdjusted:     p.handlerJuryTimePenalty,
+		models.JuryReinstated:       p.handlerJuryReinstate,
 	}
 
 	if handler, ok := handlers[event.Type]; ok {
-		return handler(c, event)
+		before := progressOf(c)
+		if err := handler(c, event); err != nil {
+			err = p.localize(err)
+			c.Note(event.Time, models.TimelineWarning, p.catalog.T("warning.rejected", err))
+			return err
+		}
+		p.noteDerived(c, before, event.Time)
+		return nil
 	}
-	return fmt.Errorf("unknown event type: %d", event.Type)
+	return errors.New(p.catalog.T("error.unknownEvent", int(event.Type)))
 }
 
 func (p *EventProcessor) GetCompetitors() []*models.Competitor {
@@ -57,37 +101,111 @@ func (p *EventProcessor) GetCompetitors() []*models.Competitor {
 	for _, c := range p.competitors {
 		list = append(list, c)
 	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
 	return list
 }
 
+func isJuryEvent(t models.EventType) bool {
+	switch t {
+	case models.JuryDisqualified, models.JuryTimeAdjusted, models.JuryReinstated, models.JuryResultsState:
+		return true
+	default:
+		return false
+	}
+}
+
+// localize выводит нарушение правил из models на языке каталога; остальные ошибки не меняются
+func (p *EventProcessor) localize(err error) error {
+	var ruleErr *models.RuleError
+	if !errors.As(err, &ruleErr) {
+		return err
+	}
+	args := make([]any, len(ruleErr.Args))
+	for i, arg := range ruleErr.Args {
+		if status, ok := arg.(models.CompetitorStatus); ok {
+			arg = competitorStatusLabel(p.catalog, status)
+		}
+		args[i] = arg
+	}
+	return errors.New(p.catalog.T("error."+ruleErr.Key, args...))
+}
+
+func (p *EventProcessor) GetJury() *models.Jury {
+	return p.jury
+}
+
 func (p *EventProcessor) getOrCreate(id int) *models.Competitor {
 	if c, exists := p.competitors[id]; exists {
 		return c
 	}
 	c := models.NewCompetitor(id, p.logger)
+	if athlete, ok := p.registry.Lookup(id); ok {
+		c.Athlete = athlete
+	}
+	c.Category = p.categoryOf(c)
 	p.competitors[id] = c
 	return c
 }
 
+// categoryOf определяет категорию участника: по реестру спортсменов, иначе по диапазону номеров.
+func (p *EventProcessor) categoryOf(c *models.Competitor) string {
+	if c.Athlete != nil {
+		if _, ok := p.config.FindCategory(c.Athlete.Category); ok {
+			return c.Athlete.Category
+		}
+	}
+	if cat, ok := p.config.CategoryForBib(c.ID); ok {
+		return cat.Name
+	}
+	return ""
+}
+
+// course возвращает параметры трассы категории участника.
+func (p *EventProcessor) course(c *models.Competitor) *models.Config {
+	return p.config.CourseFor(c.Category)
+}
+
+// startIndex - порядковый номер участника в стартовом окне его категории (с 0).
+func (p *EventProcessor) startIndex(c *models.Competitor) int {
+	cat, ok := p.config.FindCategory(c.Category)
+	if !ok {
+		return c.ID - 1
+	}
+	if cat.BibFrom > 0 && c.ID >= cat.BibFrom && c.ID <= cat.BibTo {
+		return c.ID - cat.BibFrom
+	}
+
+	// Категория назначена через реестр: порядок по номерам спортсменов этой категории
+	index := 0
+	for _, bib := range p.registry.Bibs() {
+		if athlete, _ := p.registry.Lookup(bib); bib < c.ID && athlete.Category == cat.Name {
+			index++
+		}
+	}
+	return index
+}
+
 func (p *EventProcessor) validateOrder(event models.Event, c *models.Competitor) error {
 	if !c.ActualStart.IsZero() && event.Time.Before(c.ActualStart) {
-		return errors.New("event time precedes actual start")
+		return errors.New(p.catalog.T("error.precedesStart"))
 	}
 	return nil
 }
 
-func (p *EventProcessor) calculateScheduled(id int) time.Time {
-	base := p.config.Start
-	offset := time.Duration(id-1) * p.config.StartDelta
-	return base.Add(offset)
+func (p *EventProcessor) calculateScheduled(c *models.Competitor) time.Time {
+	course := p.course(c)
+	offset := time.Duration(p.startIndex(c)) * course.StartDelta
+	return course.Start.Add(offset)
 }
 
 // Handlers:
 func (p *EventProcessor) handlerRegister(c *models.Competitor, e models.Event) error {
-	c.SetScheduled(p.calculateScheduled(c.ID))
+	c.SetScheduled(p.calculateScheduled(c))
 
 	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
-		p.logger.Info("Участник зарегистрирован",
+		p.logger.Info(p.catalog.T("log.registered"),
 			"time", utils.FormatTimestamp(e.Time),
 			"competitorID", c.ID)
 	}
@@ -96,7 +214,7 @@ func (p *EventProcessor) handlerRegister(c *models.Competitor, e models.Event) e
 
 func (p *EventProcessor) handlerSetStartTime(c *models.Competitor, e models.Event) error {
 	if len(e.ExtraParams) < 1 {
-		err := errors.New("missing start time")
+		err := errors.New(p.catalog.T("error.missingStartTime"))
 		p.logger.Error("missing start time", "error", err)
 		return err
 	}
@@ -109,7 +227,7 @@ func (p *EventProcessor) handlerSetStartTime(c *models.Competitor, e models.Even
 	c.SetScheduled(t)
 
 	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
-		p.logger.Info("Время старта участника установлено жеребьёвкой",
+		p.logger.Info(p.catalog.T("log.startTimeSet"),
 			"time", utils.FormatTimestamp(e.Time),
 			"competitorID", c.ID,
 			"startTime", e.ExtraParams[0])
@@ -119,7 +237,7 @@ func (p *EventProcessor) handlerSetStartTime(c *models.Competitor, e models.Even
 
 func (p *EventProcessor) handlerOnStartLine(c *models.Competitor, e models.Event) error {
 	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
-		p.logger.Info("Участник находится на стартовой линии",
+		p.logger.Info(p.catalog.T("log.onStartLine"),
 			"time", utils.FormatTimestamp(e.Time),
 			"competitorID", c.ID)
 	}
@@ -127,10 +245,10 @@ func (p *EventProcessor) handlerOnStartLine(c *models.Competitor, e models.Event
 }
 
 func (p *EventProcessor) handlerStartRace(c *models.Competitor, e models.Event) error {
-	sched := p.calculateScheduled(c.ID)
-	if e.Time.After(sched.Add(p.config.StartDelta)) {
+	sched := p.calculateScheduled(c)
+	if e.Time.After(sched.Add(p.course(c).StartDelta)) {
 		if p.logger.Enabled(context.Background(), slog.LevelInfo) {
-			p.logger.Info("Участник дисквалифицирован (опоздание на старт)",
+			p.logger.Info(p.catalog.T("log.lateStart"),
 				"time", utils.FormatTimestamp(e.Time),
 				"competitorID", c.ID)
 		}
@@ -144,7 +262,7 @@ func (p *EventProcessor) handlerStartRace(c *models.Competitor, e models.Event)
 
 	c.StartNewLap(false, c.Scheduled)
 	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
-		p.logger.Info("Участник начал движение",
+		p.logger.Info(p.catalog.T("log.started"),
 			"time", utils.FormatTimestamp(e.Time),
 			"competitorID", c.ID)
 	}
@@ -153,7 +271,7 @@ func (p *EventProcessor) handlerStartRace(c *models.Competitor, e models.Event)
 
 func (p *EventProcessor) handlerEnterFiring(c *models.Competitor, e models.Event) error {
 	if len(e.ExtraParams) < 1 {
-		err := errors.New("missing firing line")
+		err := errors.New(p.catalog.T("error.missingFiringLine"))
 		p.logger.Error("missing firing line:", "error", err,
 			"competitorID", c.ID, "eventTime:", e.Time, "paramsCount:", len(e.ExtraParams))
 		return err
@@ -166,20 +284,23 @@ func (p *EventProcessor) handlerEnterFiring(c *models.Competitor, e models.Event
 		return err
 	}
 
-	c.StartFiring(line, p.config.FiringLines, e.Time)
+	course := p.course(c)
+	position := course.PositionForStage(len(c.FiringLines) + 1)
+	c.StartFiring(line, course.ShotsPerStage, course.SpareRounds, position, e.Time)
 
 	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
-		p.logger.Info("Участник находится на стрелковом рубеже",
+		p.logger.Info(p.catalog.T("log.onFiringRange"),
 			"time", utils.FormatTimestamp(e.Time),
 			"competitorID", c.ID,
-			"firingLine", line)
+			"firingLine", line,
+			"position", position)
 	}
 	return c.UpdateStatus(models.InFiringRange)
 }
 
 func (p *EventProcessor) handlerHitTarget(c *models.Competitor, e models.Event) error {
 	if len(e.ExtraParams) < 1 {
-		err := errors.New("missing target number")
+		err := errors.New(p.catalog.T("error.missingTarget"))
 		p.logger.Error("the target number is missing:", "error", err,
 			"competitorID:", c.ID, "eventTime:", e.Time, "paramsCount:", len(e.ExtraParams))
 		return err
@@ -192,9 +313,16 @@ func (p *EventProcessor) handlerHitTarget(c *models.Competitor, e models.Event)
 		return err
 	}
 
-	c.RegisterShot(n)
+	if n < 1 || n > p.config.ShotsPerStage {
+		err := errors.New(p.catalog.T("error.targetOutOfRange", n, p.config.ShotsPerStage))
+		p.logger.Error("invalid target number:", "error", err,
+			"competitorID:", c.ID, "eventTime:", e.Time)
+		return err
+	}
+
+	c.RegisterShot(n, e.Time)
 	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
-		p.logger.Info("Мишень поражена участником",
+		p.logger.Info(p.catalog.T("log.targetHit"),
 			"time", utils.FormatTimestamp(e.Time),
 			"competitorID", c.ID,
 			"target", n)
@@ -202,10 +330,24 @@ func (p *EventProcessor) handlerHitTarget(c *models.Competitor, e models.Event)
 	return nil
 }
 
+func (p *EventProcessor) handlerShotFired(c *models.Competitor, e models.Event) error {
+	if err := c.RegisterRound(e.Time); err != nil {
+		return err
+	}
+
+	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
+		p.logger.Info(p.catalog.T("log.shotFired"),
+			"time", utils.FormatTimestamp(e.Time),
+			"competitorID", c.ID)
+	}
+	return nil
+}
+
 func (p *EventProcessor) handlerLeaveFiring(c *models.Competitor, e models.Event) error {
 	missed := c.FinishFiring(e.Time)
+	c.PassCheckpoint(models.RangeExit, e.Time)
 	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
-		p.logger.Info("Участник покинул стрелковый рубеж",
+		p.logger.Info(p.catalog.T("log.leftFiringRange"),
 			"time", utils.FormatTimestamp(e.Time),
 			"competitorID", c.ID)
 	}
@@ -226,7 +368,7 @@ func (p *EventProcessor) handlerEnterPenalty(c *models.Competitor, e models.Even
 	c.StartNewLap(true, e.Time)
 
 	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
-		p.logger.Info("Участник начал штрафные круги",
+		p.logger.Info(p.catalog.T("log.enteredPenalty"),
 			"time", utils.FormatTimestamp(e.Time),
 			"competitorID", c.ID)
 	}
@@ -237,7 +379,7 @@ func (p *EventProcessor) handlerLeavePenalty(c *models.Competitor, e models.Even
 	c.EndPenalty(e.Time)
 
 	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
-		p.logger.Info("Участник завершил штрафные круги",
+		p.logger.Info(p.catalog.T("log.leftPenalty"),
 			"time", utils.FormatTimestamp(e.Time),
 			"competitorID", c.ID)
 	}
@@ -250,14 +392,19 @@ func (p *EventProcessor) handlerFinishLap(c *models.Competitor, e models.Event)
 			"competitorID", c.ID, "lapNumber", len(c.Laps))
 		return err
 	}
+	c.PassCheckpoint(models.LapEnd, e.Time)
 
 	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
-		p.logger.Info("Участник завершил основной круг",
+		p.logger.Info(p.catalog.T("log.lapFinished"),
 			"time", utils.FormatTimestamp(e.Time),
 			"competitorID", c.ID)
 	}
 
-	if c.CompletedMain(p.config.Laps) {
+	if err := p.checkLapped(c, e.Time); err != nil {
+		return err
+	}
+
+	if c.CompletedMain(p.course(c).Laps) {
 		c.SetFinish(e.Time)
 		return c.UpdateStatus(models.Finished)
 	}
@@ -266,18 +413,216 @@ func (p *EventProcessor) handlerFinishLap(c *models.Competitor, e models.Event)
 	return nil
 }
 
+func (p *EventProcessor) handlerPassSplit(c *models.Competitor, e models.Event) error {
+	if len(e.ExtraParams) < 1 {
+		err := errors.New(p.catalog.T("error.missingSplit"))
+		p.logger.Error("missing split point:", "error", err,
+			"competitorID", c.ID, "eventTime:", e.Time, "paramsCount:", len(e.ExtraParams))
+		return err
+	}
+
+	id := e.ExtraParams[0]
+	if _, ok := p.config.FindSplit(id); !ok {
+		err := errors.New(p.catalog.T("error.unknownSplit", id))
+		p.logger.Error("unknown split point:", "error", err,
+			"competitorID", c.ID, "eventTime:", e.Time)
+		return err
+	}
+
+	if err := c.RecordSplit(id, e.Time); err != nil {
+		return err
+	}
+
+	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
+		p.logger.Info(p.catalog.T("log.passedSplit"),
+			"time", utils.FormatTimestamp(e.Time),
+			"competitorID", c.ID,
+			"split", id)
+	}
+	return nil
+}
+
+// checkLapped проверяет, кого обогнал на круг участник leader, только что завершивший круг.
+// Обогнанным считается участник той же категории на трассе, завершивший как минимум на два круга меньше.
+func (p *EventProcessor) checkLapped(leader *models.Competitor, t time.Time) error {
+	if p.config.LappedRule == "" {
+		return nil
+	}
+
+	laps := leader.CompletedLaps()
+	for _, c := range p.competitors {
+		if c == leader || c.Category != leader.Category || !c.OnCourse() || !c.LappedAt.IsZero() || c.CompletedLaps() >= laps-1 {
+			continue
+		}
+
+		c.LappedAt = t
+		c.Note(t, models.TimelineDerived, p.catalog.T("timeline.lappedBy", leader.ID))
+		if p.logger.Enabled(context.Background(), slog.LevelInfo) {
+			p.logger.Info(p.catalog.T("log.lapped"),
+				"time", utils.FormatTimestamp(t),
+				"competitorID", c.ID,
+				"leaderID", leader.ID,
+				"completedLaps", c.CompletedLaps())
+		}
+		if p.config.LappedRule == models.LappedPull {
+			if err := c.UpdateStatus(models.Lapped); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (p *EventProcessor) handlerCannotContinue(c *models.Competitor, e models.Event) error {
 	reason := ""
 	if len(e.ExtraParams) > 0 {
 		reason = e.ExtraParams[0]
-		c.DisqualificationReason = reason
+		// Причину дисквалификации жюри не заменяем причиной схода
+		if c.Status != models.Disqualified {
+			c.DisqualificationReason = reason
+		}
 	}
 
 	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
-		p.logger.Info("Участник не может продолжить",
+		p.logger.Info(p.catalog.T("log.cannotContinue"),
 			"time", utils.FormatTimestamp(e.Time),
 			"competitorID", c.ID,
 			"reason", reason)
 	}
 	return c.UpdateStatus(models.NotFinished)
 }
+
+// Решения жюри:
+func (p *EventProcessor) handlerJuryDisqualify(c *models.Competitor, e models.Event) error {
+	if len(e.ExtraParams) < 1 {
+		err := errors.New(p.catalog.T("error.missingRule"))
+		p.logger.Error("missing rule reference:", "error", err,
+			"competitorID", c.ID, "eventTime:", e.Time)
+		return err
+	}
+
+	action := models.JuryAction{
+		Time:         e.Time,
+		Type:         models.JuryDisqualify,
+		CompetitorID: c.ID,
+		Rule:         e.ExtraParams[0],
+		Reason:       strings.Join(e.ExtraParams[1:], " "),
+	}
+	if err := c.ApplyJuryAction(action); err != nil {
+		return err
+	}
+	p.jury.Record(action)
+
+	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
+		p.logger.Info(p.catalog.T("log.juryDisqualified"),
+			"time", utils.FormatTimestamp(e.Time),
+			"competitorID", c.ID,
+			"rule", action.Rule,
+			"reason", action.Reason)
+	}
+	return nil
+}
+
+func (p *EventProcessor) handlerJuryTimePenalty(c *models.Competitor, e models.Event) error {
+	if len(e.ExtraParams) < 1 {
+		err := errors.New(p.catalog.T("error.missingPenalty"))
+		p.logger.Error("missing penalty time:", "error", err,
+			"competitorID", c.ID, "eventTime:", e.Time)
+		return err
+	}
+
+	penalty, err := parseSignedDuration(e.ExtraParams[0])
+	if err != nil {
+		p.logger.Error("invalid penalty time:", "error", err,
+			"competitorID", c.ID, "eventTime:", e.Time, "rawInput:", e.ExtraParams[0])
+		return err
+	}
+
+	action := models.JuryAction{
+		Time:         e.Time,
+		Type:         models.JuryTimePenalty,
+		CompetitorID: c.ID,
+		Penalty:      penalty,
+		Reason:       strings.Join(e.ExtraParams[1:], " "),
+	}
+	if err := c.ApplyJuryAction(action); err != nil {
+		return err
+	}
+	p.jury.Record(action)
+
+	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
+		p.logger.Info(p.catalog.T("log.juryTimeAdjusted"),
+			"time", utils.FormatTimestamp(e.Time),
+			"competitorID", c.ID,
+			"penalty", e.ExtraParams[0],
+			"reason", action.Reason)
+	}
+	return nil
+}
+
+func (p *EventProcessor) handlerJuryReinstate(c *models.Competitor, e models.Event) error {
+	action := models.JuryAction{
+		Time:         e.Time,
+		Type:         models.JuryReinstate,
+		CompetitorID: c.ID,
+		Reason:       strings.Join(e.ExtraParams, " "),
+	}
+	if err := c.ApplyJuryAction(action); err != nil {
+		return err
+	}
+	p.jury.Record(action)
+
+	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
+		p.logger.Info(p.catalog.T("log.juryReinstated"),
+			"time", utils.FormatTimestamp(e.Time),
+			"competitorID", c.ID,
+			"reason", action.Reason)
+	}
+	return nil
+}
+
+func (p *EventProcessor) handlerResultsState(e models.Event) error {
+	if len(e.ExtraParams) < 1 {
+		err := errors.New(p.catalog.T("error.missingResultsState"))
+		p.logger.Error("missing results state:", "error", err, "eventTime:", e.Time)
+		return err
+	}
+
+	state := e.ExtraParams[0]
+	if state != models.ResultsProvisional && state != models.ResultsOfficial {
+		err := errors.New(p.catalog.T("error.unknownResultsState", state))
+		p.logger.Error("invalid results state:", "error", err, "eventTime:", e.Time)
+		return err
+	}
+
+	p.jury.Record(models.JuryAction{
+		Time:   e.Time,
+		Type:   models.JuryResultState,
+		State:  state,
+		Reason: strings.Join(e.ExtraParams[1:], " "),
+	})
+
+	if p.logger.Enabled(context.Background(), slog.LevelInfo) {
+		p.logger.Info(p.catalog.T("log.juryResultsState"),
+			"time", utils.FormatTimestamp(e.Time),
+			"state", state)
+	}
+	return nil
+}
+
+// parseSignedDuration разбирает время вида +HH:MM:SS или -HH:MM:SS.
+func parseSignedDuration(raw string) (time.Duration, error) {
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(raw, "-"):
+		sign = -1
+		raw = raw[1:]
+	case strings.HasPrefix(raw, "+"):
+		raw = raw[1:]
+	}
+	d, err := utils.ParseDuration(raw)
+	if err != nil {
+		return 0, err
+	}
+	return sign * d, nil
+}
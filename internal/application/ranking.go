@@ -0,0 +1,147 @@
+package application
+
+import (
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"sort"
+	"time"
+)
+
+// timedEntry - время участника в контрольной точке (отсечка, финиш круга и т.п.)
+type timedEntry struct {
+	competitor *models.Competitor
+	elapsed    time.Duration
+}
+
+// rankedEntry - место участника в контрольной точке и отставание от лидера
+type rankedEntry struct {
+	timedEntry
+	rank int
+	gap  time.Duration
+}
+
+// rankTimes сортирует участников по времени и расставляет места.
+// Одинаковое время делит место, при равенстве порядок определяется номером участника.
+func rankTimes(entries []timedEntry) []rankedEntry {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].elapsed != entries[j].elapsed {
+			return entries[i].elapsed < entries[j].elapsed
+		}
+		return entries[i].competitor.ID < entries[j].competitor.ID
+	})
+
+	ranked := make([]rankedEntry, len(entries))
+	for i, e := range entries {
+		rank := i + 1
+		if i > 0 && e.elapsed == entries[i-1].elapsed {
+			rank = ranked[i-1].rank
+		}
+		ranked[i] = rankedEntry{timedEntry: e, rank: rank, gap: e.elapsed - entries[0].elapsed}
+	}
+	return ranked
+}
+
+// ResultRow - строка итогового протокола
+type ResultRow struct {
+	Rank       int // Место; 0 для участников без результата (обогнанные на круг получают места после финишировавших)
+	Competitor *models.Competitor
+}
+
+// Группы статусов в порядке вывода в протоколе
+const (
+	groupFinished = iota
+	groupInProgress
+	groupLapped
+	groupNotFinished
+	groupNotStarted
+	groupDisqualified
+)
+
+func statusGroup(c *models.Competitor) int {
+	switch c.Status {
+	case models.Finished:
+		return groupFinished
+	case models.NotFinished:
+		return groupNotFinished
+	case models.NotStarted:
+		return groupNotStarted
+	case models.Disqualified:
+		return groupDisqualified
+	case models.Lapped:
+		return groupLapped
+	default:
+		return groupInProgress
+	}
+}
+
+// rowCompetitors возвращает участников строк протокола в том же порядке.
+func rowCompetitors(rows []ResultRow) []*models.Competitor {
+	competitors := make([]*models.Competitor, len(rows))
+	for i, row := range rows {
+		competitors[i] = row.Competitor
+	}
+	return competitors
+}
+
+// competitorSet строит множество участников для отбора выводимых строк
+func competitorSet(competitors []*models.Competitor) map[*models.Competitor]bool {
+	set := make(map[*models.Competitor]bool, len(competitors))
+	for _, c := range competitors {
+		set[c] = true
+	}
+	return set
+}
+
+// RankCompetitors строит протокол: финишировавшие по возрастанию времени,
+// затем участники на трассе и снятые с трассы (по числу кругов), NotFinished, NotStarted и Disqualified.
+// Внутри групп без времени порядок определяется номером участника.
+func RankCompetitors(competitors []*models.Competitor) []ResultRow {
+	sorted := make([]*models.Competitor, len(competitors))
+	copy(sorted, competitors)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if ga, gb := statusGroup(a), statusGroup(b); ga != gb {
+			return ga < gb
+		}
+		switch statusGroup(a) {
+		case groupFinished:
+			if ta, tb := a.TotalTime(), b.TotalTime(); ta != tb {
+				return ta < tb
+			}
+		case groupInProgress:
+			if la, lb := a.CompletedLaps(), b.CompletedLaps(); la != lb {
+				return la > lb
+			}
+		case groupLapped:
+			if la, lb := a.CompletedLaps(), b.CompletedLaps(); la != lb {
+				return la > lb
+			}
+			if ea, eb := a.Elapsed(a.LastLapFinish()), b.Elapsed(b.LastLapFinish()); ea != eb {
+				return ea < eb
+			}
+		}
+		return a.ID < b.ID
+	})
+
+	rows := make([]ResultRow, len(sorted))
+	for i, c := range sorted {
+		rows[i] = ResultRow{Competitor: c}
+		group := statusGroup(c)
+		if group != groupFinished && group != groupLapped {
+			continue
+		}
+		rows[i].Rank = i + 1
+		if i > 0 && statusGroup(sorted[i-1]) == group && sameResult(sorted[i-1], c) {
+			rows[i].Rank = rows[i-1].Rank
+		}
+	}
+	return rows
+}
+
+// sameResult - одинаковый результат у участников одной группы
+func sameResult(a, b *models.Competitor) bool {
+	if statusGroup(a) == groupLapped {
+		return a.CompletedLaps() == b.CompletedLaps() &&
+			a.Elapsed(a.LastLapFinish()) == b.Elapsed(b.LastLapFinish())
+	}
+	return a.TotalTime() == b.TotalTime()
+}
@@ -4,269 +4,250 @@ import (
 	"context"
 	"fmt"
 	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
-	"github.com/BiathlonRaceProto-Yadro/pkg/utils"
+	"github.com/BiathlonRaceProto-Yadro/internal/i18n"
 	"log/slog"
-	"sort"
 	"strings"
 	"text/tabwriter"
+	"text/template"
 	"time"
 )
 
+// ReportOptions - настройки содержимого отчёта
+type ReportOptions struct {
+	FullOutput    bool               // Полный табличный отчёт вместо короткого
+	Progression   bool               // Таблица мест и отставаний в контрольных точках
+	NetLaps       bool               // Чистое время кругов без штрафных кругов рядом с полным
+	ExcludeRange  bool               // Исключать из чистого времени круга время на рубеже
+	Filter        *ResultFilter      // Показывать только спортсменов, подходящих под фильтр
+	Overall       bool               // Общий зачёт по всем категориям в дополнение к протоколам категорий
+	Format        string             // Формат вывода (text, json, csv, tsv, html, xml); пусто - text
+	Layout        string             // Раскладка CSV/TSV (wide, long)
+	Delimiter     rune               // Разделитель колонок CSV/TSV; 0 - по формату
+	Charts        bool               // Встраивать SVG-графики в HTML-отчёт
+	ChartsDir     string             // Каталог для SVG-графиков отдельными файлами; пусто - не сохранять
+	ShootingStats bool               // Раздел аналитики стрельбы по рубежам, установкам, мишеням и спортсменам
+	Template      *template.Template // Пользовательский шаблон таблицы протокола (см. TemplateData); nil - встроенный
+	Tolerance     time.Duration      // Допуск расхождения времени для сравнения результатов (diff)
+	Catalog       *i18n.Catalog      // Каталог сообщений отчёта (-lang); nil - язык по умолчанию
+}
+
+// Форматы вывода отчёта
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+	OutputCSV  = "csv"
+	OutputTSV  = "tsv"
+	OutputHTML = "html"
+	OutputXML  = "xml"
+)
+
+// ParseOutputFormat проверяет значение флага -format.
+func ParseOutputFormat(raw string) (string, error) {
+	switch raw {
+	case "", OutputText:
+		return OutputText, nil
+	case OutputJSON, OutputCSV, OutputTSV, OutputHTML, OutputXML:
+		return raw, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q", raw)
+	}
+}
+
+// NewReportGenerator выбирает генератор отчёта по формату вывода.
+func NewReportGenerator(config *models.Config, options ReportOptions, logger *slog.Logger) ReportGenerator {
+	switch options.Format {
+	case OutputJSON:
+		return NewJSONReportService(options, logger)
+	case OutputCSV, OutputTSV:
+		return NewCSVReportService(options, logger)
+	case OutputHTML:
+		return NewHTMLReportService(options, logger)
+	case OutputXML:
+		return NewXMLReportService(options, logger)
+	default:
+		return NewReportService(config, options, logger)
+	}
+}
+
 type ReportService struct {
-	config     *models.Config
-	fullOutput bool
-	logger     *slog.Logger
+	config  *models.Config
+	options ReportOptions
+	logger  *slog.Logger
 }
 
-func NewReportService(config *models.Config, fullOutput bool, logger *slog.Logger) ReportGenerator {
+func NewReportService(config *models.Config, options ReportOptions, logger *slog.Logger) ReportGenerator {
 	return &ReportService{
-		config:     config,
-		fullOutput: fullOutput,
-		logger:     logger,
+		config:  config,
+		options: options,
+		logger:  logger,
 	}
 }
 
-func (r *ReportService) GenerateReport(competitors []*models.Competitor, _ *models.Config) string {
-	if r.fullOutput {
-		if r.logger.Enabled(context.Background(), slog.LevelDebug) {
-			r.logger.Debug("Generating full report", "competitorsCount", len(competitors))
+func (r *ReportService) GenerateReport(race *models.Race) (string, error) {
+	result := BuildRaceResult(race, r.options)
+
+	var parts []string
+	for i, scope := range raceScopes(race, r.options.Overall) {
+		part, err := r.generateScope(result, result.Classifications[i], scope)
+		if err != nil {
+			return "", err
 		}
-		return r.generateFullReport(competitors)
+		parts = append(parts, part)
 	}
+	report := strings.Join(parts, "\n")
 
-	if r.logger.Enabled(context.Background(), slog.LevelDebug) {
-		r.logger.Debug("Generating short report", "competitorsCount", len(competitors))
-	}
-	return r.generateShortReport(competitors)
+	report += r.generateJuryReport(result.Jury)
+	report += r.generateWarningsReport(result.Warnings)
+	return report, nil
 }
 
-// Короткий отчёт
-func (r *ReportService) generateShortReport(competitors []*models.Competitor) string {
-	sort.Slice(competitors, func(i, j int) bool {
-		iTime, jTime := competitors[i].TotalTime(), competitors[j].TotalTime()
-		if iTime == 0 || jTime == 0 {
-			return iTime > jTime // push non-finishers to bottom
+// RenderResult выводит загруженный результат: основные протоколы и командный зачёт
+// (разделы, требующие событий гонки, недоступны).
+func (r *ReportService) RenderResult(result *RaceResult) (string, error) {
+	var parts []string
+	for _, cl := range result.Classifications {
+		report, err := r.generateResultsTable(result, cl)
+		if err != nil {
+			return "", err
 		}
-		return iTime < jTime
-	})
+		report += r.generateLappedReport(cl)
+		if cl.ShootingStats != nil {
+			report += r.generateShootingStatsReport(cl.ShootingStats)
+		}
+		if len(cl.Teams) > 0 {
+			report += r.generateTeamReport(result, cl)
+		}
+		parts = append(parts, report)
+	}
+	report := strings.Join(parts, "\n")
 
-	var sb strings.Builder
-	sb.WriteString("Final Results:\n")
-	for _, c := range competitors {
-		status := "[" + r.getStatusString(c) + "]"
-		id := c.ID
+	report += r.generateJuryReport(result.Jury)
+	report += r.generateWarningsReport(result.Warnings)
+	return report, nil
+}
 
-		var lapsInfo []string
-		mainLaps := c.MainLaps()
-		for _, lap := range mainLaps {
-			if lap.Finish.IsZero() {
-				lapsInfo = append(lapsInfo, "{,}")
-				continue
-			}
-			dur := lap.Finish.Sub(lap.Start)
-			speed := float64(r.config.LapLen) / dur.Seconds()
-			lapsInfo = append(lapsInfo, fmt.Sprintf("{%s, %.3f}", utils.FormatTimestamp(lap.Finish), speed))
-		}
+// raceScope - группа участников с отдельным протоколом
+type raceScope struct {
+	name    string // Пусто для гонки без категорий
+	members []*models.Competitor
+}
+
+// raceScopes делит гонку на протоколы: вся гонка или по одному на категорию
+// (участники без категории - "Uncategorized"), при overall - ещё общий зачёт.
+func raceScopes(race *models.Race, overall bool) []raceScope {
+	if len(race.Config.Categories) == 0 {
+		return []raceScope{{members: race.Competitors}}
+	}
 
-		penaltyLaps := c.PenaltyLaps()
-		var totalPenaltyTime time.Duration
-		var totalPenaltyDistance float64
-		for i, lap := range penaltyLaps {
-			if lap.Finish.IsZero() {
-				continue
-			}
-			duration := lap.Finish.Sub(lap.Start)
-			totalPenaltyTime += duration
-			totalPenaltyDistance += float64(c.PenaltyMissedShots()[i] * r.config.PenaltyLen)
+	groups := make(map[string][]*models.Competitor)
+	for _, c := range race.Competitors {
+		groups[c.Category] = append(groups[c.Category], c)
+	}
+	var scopes []raceScope
+	for _, cat := range race.Config.Categories {
+		if members := groups[cat.Name]; len(members) > 0 {
+			scopes = append(scopes, raceScope{name: cat.Name, members: members})
 		}
-		penaltyTimeStr := "-"
-		penaltySpeedStr := "-"
-		if totalPenaltyTime > 0 {
-			penaltyTimeStr = utils.FormatDuration(totalPenaltyTime)
-			penaltySpeed := totalPenaltyDistance / totalPenaltyTime.Seconds()
-			penaltySpeedStr = fmt.Sprintf("%.3f", penaltySpeed)
+	}
+	if members := groups[""]; len(members) > 0 {
+		scopes = append(scopes, raceScope{name: "Uncategorized", members: members})
+	}
+	if overall {
+		scopes = append(scopes, raceScope{name: "Overall", members: race.Competitors})
+	}
+	return scopes
+}
+
+// generateScope строит протокол для группы участников (вся гонка, категория или общий зачёт).
+// Основная таблица выводится из структурированного результата, дополнительные разделы - по данным участников.
+func (r *ReportService) generateScope(result *RaceResult, cl Classification, scope raceScope) (string, error) {
+	// Места и отставания считаются по всему протоколу, фильтр отбирает только выводимые строки
+	ranked := RankCompetitors(scope.members)
+	field := rowCompetitors(ranked)
+	competitors := rowCompetitors(r.options.Filter.Apply(ranked))
+
+	if r.logger.Enabled(context.Background(), slog.LevelDebug) {
+		msg := "Generating short report"
+		if r.options.FullOutput {
+			msg = "Generating full report"
 		}
+		r.logger.Debug(msg, "scope", scope.name, "competitorsCount", len(competitors))
+	}
+	report, err := r.generateResultsTable(result, cl)
+	if err != nil {
+		return "", err
+	}
+	if r.options.FullOutput {
+		report += r.generateSplitReport(field, competitors, r.config.CourseFor(scope.name))
+		report += r.generatePositionReport(field, competitors)
+		report += r.generateShootingTimesReport(competitors)
+	}
+	report += r.generateLappedReport(cl)
 
-		sb.WriteString(fmt.Sprintf(
-			"%s %d [%s] {%s, %s} %d/%d\n",
-			status,
-			id,
-			strings.Join(lapsInfo, ", "),
-			penaltyTimeStr,
-			penaltySpeedStr,
-			c.Hits,
-			c.Shots,
-		))
+	if r.options.Progression {
+		report += r.generateProgressionReport(field, competitors)
 	}
-	return sb.String()
+	if cl.ShootingStats != nil {
+		report += r.generateShootingStatsReport(cl.ShootingStats)
+	}
+	if len(cl.Teams) > 0 {
+		report += r.generateTeamReport(result, cl)
+	}
+	return report, nil
 }
 
-// Полный табличный отчёт
-func (r *ReportService) generateFullReport(competitors []*models.Competitor) string {
-	sort.Slice(competitors, func(i, j int) bool {
-		iTime, jTime := competitors[i].TotalTime(), competitors[j].TotalTime()
-		if iTime == 0 || jTime == 0 {
-			return iTime > jTime // Опускаем в них тех, кто не финишировал
+// generateLappedReport перечисляет участников, которых лидер обогнал на круг (lappedRule):
+// при правиле flag это единственный след обгона в протоколе.
+func (r *ReportService) generateLappedReport(cl Classification) string {
+	var lapped []CompetitorResult
+	for _, res := range cl.Results {
+		if res.LappedAt != "" {
+			lapped = append(lapped, res)
 		}
-		return iTime < jTime
-	})
+	}
+	if len(lapped) == 0 {
+		return ""
+	}
 
 	var sb strings.Builder
-	sb.WriteString("Final Results:\n")
+	sb.WriteString("\n" + r.options.Catalog.T("report.lapped") + ":\n")
 	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
-	if _, err := fmt.Fprintln(w, "ID\tStatus\tTotal Time\tLaps Times\tSpeed Laps\tPenalty Times\tSpeed Penalty\tHits/Shots"); err != nil {
+	header, separator := tableHeader(r.options.Catalog, "col.id", "col.lappedAt", "col.status")
+	if _, err := fmt.Fprintln(w, header); err != nil {
 		r.logger.Error("failed to write header", "error", err)
 	}
-	if _, err := fmt.Fprintln(w, "--\t------\t----------\t----------\t----------\t-------------\t-------------\t----------"); err != nil {
+	if _, err := fmt.Fprintln(w, separator); err != nil {
 		r.logger.Error("failed to write separator", "error", err)
 	}
-
-	for _, c := range competitors {
-		timeStr := "-"
-		if d := c.TotalTime(); d > 0 {
-			timeStr = utils.FormatDuration(d)
-		}
-
-		status := r.getStatusString(c)
-		mainTimes := r.formatMainLapsDirty(c)
-		mainSpeeds := r.formatMainSpeeds(c)
-		penTimes := r.formatPenaltyTimes(c)
-		penSpeeds := r.formatPenaltySpeeds(c)
-
-		row := fmt.Sprintf(
-			"%d\t%s\t%s\t%s\t%s\t%s\t%s\t%d/%d",
-			c.ID,
-			status,
-			timeStr,
-			mainTimes,
-			mainSpeeds,
-			penTimes,
-			penSpeeds,
-			c.Hits,
-			c.Shots,
-		)
-		if _, err := fmt.Fprintln(w, row); err != nil {
+	for _, res := range lapped {
+		if _, err := fmt.Fprintf(w, "%d\t%s\t%s\n", res.ID, res.LappedAt, res.Status); err != nil {
 			r.logger.Error("failed to write row", "error", err)
 		}
 	}
-
 	if err := w.Flush(); err != nil {
 		r.logger.Error("failed to flush tabwriter", "error", err)
 	}
 	return sb.String()
 }
 
-func (r *ReportService) getStatusString(c *models.Competitor) string {
-	switch c.Status {
-	//case c.DisqualificationReason == "NotStarted":
-	//	return "NotStarted"
-	case models.NotStarted:
-		return "NotStarted"
-	case models.NotFinished:
-		return "NotFinished"
-	case models.Finished:
-		return "Finished"
-	case models.Disqualified:
-		return "Disqualified"
+// generateResultsTable выводит основную таблицу протокола: пользовательский шаблон (-template)
+// или встроенный короткий или полный.
+func (r *ReportService) generateResultsTable(result *RaceResult, cl Classification) (string, error) {
+	tmpl := r.options.Template
+	switch {
+	case tmpl != nil:
+	case r.options.FullOutput:
+		tmpl = fullTemplate
 	default:
-		return "InProgress"
+		tmpl = shortTemplate
 	}
+	return r.executeReportTemplate(tmpl, result, cl)
 }
 
-// Получения чистых основных кругов
-/*
-func (r *ReportService) formatMainLapsDirtyClean(c *models.Competitor) string {
-	mainLaps := c.MainLaps()
-	penaltyLaps := c.PenaltyLaps()
-
-	// Создаем новый массив кругов с скорректированным временем
-	adjustedLaps := make([]models.Lap, len(mainLaps))
-
-	for i, mainLap := range mainLaps {
-		// Копируем основной круг
-		adjustedLap := mainLap
-
-		// Если круг завершен, вычисляем время с учетом штрафов
-		if !mainLap.Finish.IsZero() {
-			lapDuration := mainLap.Finish.Sub(mainLap.Start)
-
-			// Находим все штрафные круги, которые были выполнены во время этого основного круга
-			penaltyTime := time.Duration(0)
-			for _, penaltyLap := range penaltyLaps {
-				if !penaltyLap.Finish.IsZero() &&
-					penaltyLap.Start.After(mainLap.Start) &&
-					penaltyLap.Finish.Before(mainLap.Finish) {
-					penaltyTime += penaltyLap.Finish.Sub(penaltyLap.Start)
-				}
-			}
-
-			// Корректируем время круга
-			adjustedDuration := lapDuration - penaltyTime
-			if adjustedDuration < 0 {
-				adjustedDuration = 0
-			}
-			adjustedLap.Finish = adjustedLap.Start.Add(adjustedDuration)
-		}
-
-		adjustedLaps[i] = adjustedLap
-	}
-
-	return r.formatLaps(adjustedLaps)
-}
-*/
-
-// Получение грязных основных кругов
-func (r *ReportService) formatMainLapsDirty(c *models.Competitor) string {
-	return r.formatLaps(c.MainLaps())
-}
-
-func (r *ReportService) formatMainSpeeds(c *models.Competitor) string {
-	return r.formatSpeeds(c.MainLaps(), r.config.LapLen)
-}
-
-func (r *ReportService) formatPenaltyTimes(c *models.Competitor) string {
-	return r.formatLaps(c.PenaltyLaps())
-}
-
-func (r *ReportService) formatPenaltySpeeds(c *models.Competitor) string {
-	return r.formatSpeedsPenalty(c.PenaltyLaps(), c.PenaltyMissedShots())
-}
-
-// Вспомогательные функции.
-func (r *ReportService) formatLaps(laps []models.Lap) string {
-	var times []string
-	for _, lap := range laps {
-		if !lap.Finish.IsZero() {
-			times = append(times, utils.FormatDuration(lap.Finish.Sub(lap.Start)))
-		}
-	}
-	return strings.Join(times, ", ")
-}
-
-func (r *ReportService) formatSpeeds(laps []models.Lap, distance int) string {
-	var speeds []string
-	for _, lap := range laps {
-		if !lap.Finish.IsZero() {
-			dur := lap.Finish.Sub(lap.Start).Seconds()
-			speed := float64(distance) / dur
-			speeds = append(speeds, fmt.Sprintf("%.3f", speed))
-		}
-	}
-	return strings.Join(speeds, ", ")
-}
-
-func (r *ReportService) formatSpeedsPenalty(laps []models.Lap, missedShots []int) string {
-	var speeds []string
-	for i, lap := range laps {
-		if missedShots[i] <= 0 {
-			continue
-		}
-		if !lap.Finish.IsZero() {
-			dur := lap.Finish.Sub(lap.Start).Seconds()
-			distance := missedShots[i] * r.config.PenaltyLen
-			speed := float64(distance) / dur
-			speeds = append(speeds, fmt.Sprintf("%.3f", speed))
-		}
+// formatSpeedValue - скорость с тремя знаками; нулевая скорость (нет времени) выводится как "-"
+func formatSpeedValue(v float64) string {
+	if v <= 0 {
+		return "-"
 	}
-	return strings.Join(speeds, ", ")
+	return fmt.Sprintf("%.3f", v)
 }
@@ -0,0 +1,176 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+)
+
+// AthleteCardReportService выводит карточку участника текстом или в JSON
+type AthleteCardReportService struct {
+	options ReportOptions
+	logger  *slog.Logger
+}
+
+func NewAthleteCardReportService(options ReportOptions, logger *slog.Logger) *AthleteCardReportService {
+	return &AthleteCardReportService{
+		options: options,
+		logger:  logger,
+	}
+}
+
+func (r *AthleteCardReportService) GenerateCard(card *AthleteCard) string {
+	if r.options.Format == OutputJSON {
+		data, err := json.MarshalIndent(card, "", "  ")
+		if err != nil {
+			r.logger.Error("failed to encode athlete card", "error", err)
+			return ""
+		}
+		return string(data)
+	}
+
+	msg := r.options.Catalog
+	res := card.Result
+	var sb strings.Builder
+	sb.WriteString(msg.T("card.title", res.ID))
+	if res.Athlete != nil {
+		sb.WriteString(fmt.Sprintf(" (%s, %s)", res.Athlete.Name, res.Athlete.Nation))
+	}
+	sb.WriteString("\n")
+	if card.Scope != "" {
+		sb.WriteString(msg.T("card.classification", scopeTitle(msg, card.Scope)) + "\n")
+	}
+	rank := "-"
+	if res.Rank > 0 {
+		rank = fmt.Sprint(res.Rank)
+	}
+	sb.WriteString(msg.T("card.status", res.Status, rank, cardDuration(res.TotalTime)))
+	if res.GapToLeader != nil && res.GapToLeader.Ms > 0 {
+		sb.WriteString(", +" + res.GapToLeader.Text)
+	}
+	sb.WriteString("\n")
+	if res.DisqualificationReason != "" && res.StatusCode == string(models.CodeDisqualified) {
+		sb.WriteString(msg.T("card.reason", res.DisqualificationReason) + "\n")
+	}
+
+	sb.WriteString(r.generateTimeline(card.Timeline))
+	sb.WriteString(r.generateLaps(res))
+	sb.WriteString(r.generateTotals(card))
+
+	if len(card.Warnings) > 0 {
+		sb.WriteString("\n" + msg.T("report.warnings") + ":\n")
+		for _, w := range card.Warnings {
+			sb.WriteString("- " + w + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// Хронология: предупреждения помечаются "!" (см. card.kind.warning в каталоге)
+func (r *AthleteCardReportService) generateTimeline(entries []CardEntry) string {
+	msg := r.options.Catalog
+	var sb strings.Builder
+	sb.WriteString("\n" + msg.T("card.timeline") + ":\n")
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	header, separator := tableHeader(msg, "col.time", "col.kind", "col.details")
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		r.logger.Error("failed to write header", "error", err)
+	}
+	if _, err := fmt.Fprintln(w, separator); err != nil {
+		r.logger.Error("failed to write separator", "error", err)
+	}
+	for _, e := range entries {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", e.Time, msg.T("card.kind."+e.Kind), e.Text); err != nil {
+			r.logger.Error("failed to write row", "error", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		r.logger.Error("failed to flush tabwriter", "error", err)
+	}
+	return sb.String()
+}
+
+// Круги, штрафные круги и рубежи одной таблицей
+func (r *AthleteCardReportService) generateLaps(res CompetitorResult) string {
+	msg := r.options.Catalog
+	var sb strings.Builder
+	sb.WriteString("\n" + msg.T("card.laps") + ":\n")
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	header, separator := tableHeader(msg, "col.item", "col.number", "col.time", "col.speed", "col.details")
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		r.logger.Error("failed to write header", "error", err)
+	}
+	if _, err := fmt.Fprintln(w, separator); err != nil {
+		r.logger.Error("failed to write separator", "error", err)
+	}
+	writeRow := func(row string) {
+		if _, err := fmt.Fprintln(w, row); err != nil {
+			r.logger.Error("failed to write row", "error", err)
+		}
+	}
+	for _, lap := range res.Laps {
+		details := ""
+		if lap.Finish != "" {
+			details = msg.T("card.finish", lap.Finish)
+		}
+		if lap.NetDuration != nil {
+			details += ", " + msg.T("card.net", lap.NetDuration.Text)
+		}
+		writeRow(fmt.Sprintf("%s\t%d\t%s\t%s\t%s", msg.T("card.item.lap"), lap.Lap, cardDuration(lap.Duration), formatSpeedValue(lap.Speed), details))
+	}
+	for _, pl := range res.PenaltyLaps {
+		writeRow(fmt.Sprintf("%s\t%d\t%s\t%s\t%s", msg.T("card.item.penalty"),
+			pl.Index, cardDuration(pl.Duration), formatSpeedValue(pl.Speed), msg.T("card.penaltyDetails", pl.Missed, pl.Distance)))
+	}
+	for _, st := range res.Shooting {
+		position := positionLabel(msg, st.Position)
+		if position == "" {
+			position = "-"
+		}
+		writeRow(fmt.Sprintf("%s\t%d\t%s\t-\t%s", msg.T("card.item.stage"),
+			st.Stage, cardDuration(st.RangeTime), msg.T("card.stageDetails", st.Line, position, st.Hits, st.Targets, st.Rounds)))
+	}
+	if err := w.Flush(); err != nil {
+		r.logger.Error("failed to flush tabwriter", "error", err)
+	}
+	return sb.String()
+}
+
+func (r *AthleteCardReportService) generateTotals(card *AthleteCard) string {
+	msg := r.options.Catalog
+	t := card.Totals
+	var sb strings.Builder
+	sb.WriteString("\n" + msg.T("card.totals") + ":\n")
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	rows := []string{
+		fmt.Sprintf("%s\t%d", msg.T("card.completedLaps"), t.CompletedLaps),
+		msg.T("card.raceTime") + "\t" + cardDuration(t.RaceTime),
+		msg.T("card.skiTime") + "\t" + cardDuration(t.SkiTime),
+		msg.T("card.rangeTime") + "\t" + cardDuration(t.RangeTime),
+		msg.T("card.shootingTime") + "\t" + cardDuration(t.ShootingTime),
+		msg.T("card.penaltyTime") + "\t" + msg.T("card.penaltyTotal", cardDuration(t.PenaltyTime), t.PenaltyDistance),
+		fmt.Sprintf("%s\t%d/%d (%.1f%%)", msg.T("card.shooting"), card.Result.Hits, card.Result.Shots, card.Result.Accuracy),
+	}
+	if adj := card.Result.TimeAdjustment; adj != nil {
+		rows = append(rows, msg.T("card.juryAdjustment")+"\t"+adj.Text)
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(w, row); err != nil {
+			r.logger.Error("failed to write row", "error", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		r.logger.Error("failed to flush tabwriter", "error", err)
+	}
+	return sb.String()
+}
+
+func cardDuration(d *Duration) string {
+	if d == nil {
+		return "-"
+	}
+	return d.Text
+}
@@ -0,0 +1,246 @@
+package application
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Раскладки табличной выгрузки
+const (
+	LayoutWide = "wide" // Строка на участника, круги в колонках
+	LayoutLong = "long" // Строка на круг, штрафной круг и рубеж
+)
+
+// ParseLayout проверяет значение флага -layout.
+func ParseLayout(raw string) (string, error) {
+	switch raw {
+	case "", LayoutWide:
+		return LayoutWide, nil
+	case LayoutLong:
+		return raw, nil
+	default:
+		return "", fmt.Errorf("unknown layout %q", raw)
+	}
+}
+
+// ParseDelimiter разбирает разделитель колонок: один символ или tab.
+func ParseDelimiter(raw string) (rune, error) {
+	if raw == "tab" || raw == `\t` {
+		return '\t', nil
+	}
+	if utf8.RuneCountInString(raw) != 1 {
+		return 0, fmt.Errorf("delimiter must be a single character")
+	}
+	r, _ := utf8.DecodeRuneInString(raw)
+	if r == '"' || r == '\r' || r == '\n' {
+		return 0, fmt.Errorf("delimiter %q is not allowed", raw)
+	}
+	return r, nil
+}
+
+// CSVReportService выгружает структурированный результат в CSV/TSV
+type CSVReportService struct {
+	options ReportOptions
+	logger  *slog.Logger
+}
+
+func NewCSVReportService(options ReportOptions, logger *slog.Logger) ReportGenerator {
+	return &CSVReportService{
+		options: options,
+		logger:  logger,
+	}
+}
+
+func (r *CSVReportService) GenerateReport(race *models.Race) (string, error) {
+	return r.RenderResult(BuildRaceResult(race, r.options))
+}
+
+func (r *CSVReportService) RenderResult(result *RaceResult) (string, error) {
+	var records [][]string
+	if r.options.Layout == LayoutLong {
+		records = longRecords(result)
+	} else {
+		records = wideRecords(result, maxLaps(result))
+	}
+
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Comma = r.delimiter()
+	if err := w.WriteAll(records); err != nil {
+		return "", fmt.Errorf("failed to write csv: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// delimiter: явно заданный разделитель, иначе табуляция для TSV и запятая для CSV
+func (r *CSVReportService) delimiter() rune {
+	if r.options.Delimiter != 0 {
+		return r.options.Delimiter
+	}
+	if r.options.Format == OutputTSV {
+		return '\t'
+	}
+	return ','
+}
+
+// maxLaps - число колонок кругов: не меньше числа кругов основной дистанции
+// и достаточное для категорий с более длинной дистанцией
+func maxLaps(result *RaceResult) int {
+	laps := result.Race.Laps
+	for _, cl := range result.Classifications {
+		for _, res := range cl.Results {
+			if len(res.Laps) > laps {
+				laps = len(res.Laps)
+			}
+		}
+	}
+	return laps
+}
+
+// Широкая раскладка: по строке на участника, круги 1..laps в колонках
+func wideRecords(result *RaceResult, laps int) [][]string {
+	header := []string{
+		"Classification", "Rank", "ID", "Name", "Nation", "Club", "Birth Year", "Category",
+		"Status Code", "Status", "Total Time", "Total Time Ms", "Gap", "Gap Ms",
+	}
+	for i := 1; i <= laps; i++ {
+		header = append(header,
+			fmt.Sprintf("Lap %d Time", i),
+			fmt.Sprintf("Lap %d Ms", i),
+			fmt.Sprintf("Lap %d Speed", i),
+		)
+	}
+	header = append(header, "Penalty Laps", "Penalty Time", "Penalty Time Ms", "Hits", "Shots", "Accuracy", "Lapped At")
+
+	records := [][]string{header}
+	for _, cl := range result.Classifications {
+		for _, res := range cl.Results {
+			record := competitorColumns(cl.Scope, res)
+			record = append(record,
+				res.StatusCode,
+				res.Status,
+				durationText(res.TotalTime),
+				durationMs(res.TotalTime),
+				durationText(res.GapToLeader),
+				durationMs(res.GapToLeader),
+			)
+			for i := 0; i < laps; i++ {
+				if i < len(res.Laps) && res.Laps[i].Duration != nil {
+					lap := res.Laps[i]
+					record = append(record, durationText(lap.Duration), durationMs(lap.Duration), formatFloat(lap.Speed))
+					continue
+				}
+				record = append(record, "", "", "")
+			}
+
+			var penalty *Duration
+			var penaltyMs int64
+			for _, p := range res.PenaltyLaps {
+				if p.Duration != nil {
+					penaltyMs += p.Duration.Ms
+					penalty = newDuration(time.Duration(penaltyMs) * time.Millisecond)
+				}
+			}
+			record = append(record,
+				strconv.Itoa(penaltyLoops(res)),
+				durationText(penalty),
+				durationMs(penalty),
+				strconv.Itoa(res.Hits),
+				strconv.Itoa(res.Shots),
+				strconv.FormatFloat(res.Accuracy, 'f', 1, 64),
+				res.LappedAt,
+			)
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// Длинная раскладка: по строке на основной круг, штрафной круг и рубеж
+func longRecords(result *RaceResult) [][]string {
+	records := [][]string{{
+		"Classification", "Rank", "ID", "Name", "Nation", "Club", "Birth Year", "Category",
+		"Type", "Index", "Finish", "Time", "Ms", "Speed", "Net Time", "Net Ms", "Net Speed",
+		"Line", "Position", "Targets", "Rounds", "Hits", "Missed", "Distance",
+	}}
+	for _, cl := range result.Classifications {
+		for _, res := range cl.Results {
+			base := competitorColumns(cl.Scope, res)
+			row := func(kind string, index int, values ...string) []string {
+				record := append(append([]string{}, base...), kind, strconv.Itoa(index))
+				return append(record, values...)
+			}
+
+			for _, lap := range res.Laps {
+				records = append(records, row("lap", lap.Lap,
+					lap.Finish,
+					durationText(lap.Duration), durationMs(lap.Duration), formatFloat(lap.Speed),
+					durationText(lap.NetDuration), durationMs(lap.NetDuration), formatFloat(lap.NetSpeed),
+					"", "", "", "", "", "", "",
+				))
+			}
+			for _, p := range res.PenaltyLaps {
+				records = append(records, row("penalty", p.Index,
+					"",
+					durationText(p.Duration), durationMs(p.Duration), formatFloat(p.Speed),
+					"", "", "",
+					"", "", "", "", "", strconv.Itoa(p.Missed), strconv.Itoa(p.Distance),
+				))
+			}
+			for _, s := range res.Shooting {
+				records = append(records, row("shooting", s.Stage,
+					"",
+					durationText(s.RangeTime), durationMs(s.RangeTime), "",
+					"", "", "",
+					strconv.Itoa(s.Line), s.Position, strconv.Itoa(s.Targets), strconv.Itoa(s.Rounds),
+					strconv.Itoa(s.Hits), strconv.Itoa(s.Missed), "",
+				))
+			}
+		}
+	}
+	return records
+}
+
+// Общие колонки участника в начале каждой строки
+func competitorColumns(scope string, res CompetitorResult) []string {
+	rank := ""
+	if res.Rank > 0 {
+		rank = strconv.Itoa(res.Rank)
+	}
+	var name, nation, club, birthYear string
+	if res.Athlete != nil {
+		name, nation, club = res.Athlete.Name, res.Athlete.Nation, res.Athlete.Club
+		if res.Athlete.BirthYear > 0 {
+			birthYear = strconv.Itoa(res.Athlete.BirthYear)
+		}
+	}
+	return []string{scope, rank, strconv.Itoa(res.ID), name, nation, club, birthYear, res.Category}
+}
+
+func durationText(d *Duration) string {
+	if d == nil {
+		return ""
+	}
+	return d.Text
+}
+
+func durationMs(d *Duration) string {
+	if d == nil {
+		return ""
+	}
+	return strconv.FormatInt(d.Ms, 10)
+}
+
+// formatFloat - скорость с тремя знаками; нулевая скорость означает отсутствие данных
+func formatFloat(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', 3, 64)
+}
@@ -0,0 +1,131 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+)
+
+// DiffReportService выводит различия результатов текстом или в JSON
+type DiffReportService struct {
+	options ReportOptions
+	logger  *slog.Logger
+}
+
+func NewDiffReportService(options ReportOptions, logger *slog.Logger) *DiffReportService {
+	return &DiffReportService{
+		options: options,
+		logger:  logger,
+	}
+}
+
+func (r *DiffReportService) GenerateDiff(diff *ResultDiff) string {
+	if r.options.Format == OutputJSON {
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			r.logger.Error("failed to encode results diff", "error", err)
+			return ""
+		}
+		return string(data)
+	}
+
+	msg := r.options.Catalog
+	var sb strings.Builder
+	sb.WriteString(msg.T("diff.title", diff.Tolerance.Text) + ":\n")
+	if len(diff.Classifications) == 0 {
+		sb.WriteString(msg.T("diff.none") + "\n")
+		return sb.String()
+	}
+	for _, cd := range diff.Classifications {
+		sb.WriteString("\n")
+		if cd.Scope != "" {
+			sb.WriteString(msg.T("card.classification", scopeTitle(msg, cd.Scope)) + "\n")
+		}
+		if len(cd.Added) > 0 {
+			sb.WriteString(msg.T("diff.added", joinIDs(cd.Added)) + "\n")
+		}
+		if len(cd.Removed) > 0 {
+			sb.WriteString(msg.T("diff.removed", joinIDs(cd.Removed)) + "\n")
+		}
+		if len(cd.Competitors) > 0 {
+			sb.WriteString(r.generateChanges(cd.Competitors))
+		}
+	}
+	if diff.Exceeded {
+		sb.WriteString("\n" + msg.T("diff.exceeded") + "\n")
+	} else {
+		sb.WriteString("\n" + msg.T("diff.within") + "\n")
+	}
+	return sb.String()
+}
+
+// Одна строка на изменение; изменения времени в пределах допуска помечаются "~"
+func (r *DiffReportService) generateChanges(competitors []CompetitorDiff) string {
+	msg := r.options.Catalog
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	header, separator := tableHeader(msg, "col.id", "col.name", "col.change", "col.before", "col.after", "col.delta")
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		r.logger.Error("failed to write header", "error", err)
+	}
+	if _, err := fmt.Fprintln(w, separator); err != nil {
+		r.logger.Error("failed to write separator", "error", err)
+	}
+	for _, c := range competitors {
+		name := c.Name
+		if name == "" {
+			name = "-"
+		}
+		writeRow := func(change, before, after, delta string) {
+			if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", c.ID, name, change, before, after, delta); err != nil {
+				r.logger.Error("failed to write row", "error", err)
+			}
+		}
+		if c.Status != nil {
+			writeRow(msg.T("diff.status"), c.Status.Before, c.Status.After, "")
+		}
+		if c.Rank != nil {
+			writeRow(msg.T("diff.rank"), formatRank(c.Rank.Before), formatRank(c.Rank.After), "")
+		}
+		if c.TotalTime != nil {
+			writeRow(msg.T("diff.total"), cardDuration(c.TotalTime.Before), cardDuration(c.TotalTime.After), formatDelta(*c.TotalTime))
+		}
+		for _, lap := range c.Laps {
+			writeRow(msg.T("diff.lap", lap.Lap), cardDuration(lap.Before), cardDuration(lap.After), formatDelta(lap.TimeChange))
+		}
+		for _, st := range c.Shooting {
+			writeRow(msg.T("diff.stage", st.Stage),
+				msg.T("diff.hitsMissed", st.HitsBefore, st.MissedBefore),
+				msg.T("diff.hitsMissed", st.HitsAfter, st.MissedAfter),
+				msg.T("diff.hitsDelta", st.HitsAfter-st.HitsBefore))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		r.logger.Error("failed to flush tabwriter", "error", err)
+	}
+	return sb.String()
+}
+
+func formatDelta(tc TimeChange) string {
+	if tc.Delta == nil {
+		return "-"
+	}
+	text := tc.Delta.Text
+	if tc.Delta.Ms > 0 {
+		text = "+" + text
+	}
+	if tc.WithinTolerance {
+		text += " ~"
+	}
+	return text
+}
+
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprint(id)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,107 @@
+package application
+
+import (
+	_ "embed"
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"github.com/BiathlonRaceProto-Yadro/internal/i18n"
+	"html/template"
+	"log/slog"
+	"strings"
+)
+
+//go:embed templates/results.html.tmpl
+var resultsHTMLTemplate string
+
+// Функции шаблона HTML-отчёта
+var htmlFuncs = template.FuncMap{
+	"join": strings.Join,
+	"durationText": func(d *Duration) string {
+		if d == nil {
+			return "-"
+		}
+		return d.Text
+	},
+	// Ключи сортировки: пустое значение сортируется после всех чисел
+	"durationKey": func(d *Duration) string {
+		if d == nil {
+			return ""
+		}
+		return fmt.Sprint(d.Ms)
+	},
+	"rankKey": func(rank int) string {
+		if rank == 0 {
+			return ""
+		}
+		return fmt.Sprint(rank)
+	},
+	"speed":        formatSpeedValue,
+	"penaltyLoops": penaltyLoops,
+	"svg":          func(s string) template.HTML { return template.HTML(s) },
+	// Сообщения каталога; заменяются каталогом отчёта при выводе
+	"t":          i18n.Default().T,
+	"scopeTitle": func(scope string) string { return scope },
+	"position":   func(position string) string { return position },
+}
+
+var resultsHTML = template.Must(template.New("results").Funcs(htmlFuncs).Parse(resultsHTMLTemplate))
+
+// htmlReportData - данные страницы результатов
+type htmlReportData struct {
+	Lang   string
+	Title  string
+	Result *RaceResult
+	Charts []ScopeCharts // Графики по протоколам (индексы совпадают с Classifications); nil - без графиков
+}
+
+// HTMLReportService строит самодостаточную HTML-страницу результатов (стили и скрипты встроены)
+type HTMLReportService struct {
+	options ReportOptions
+	logger  *slog.Logger
+}
+
+func NewHTMLReportService(options ReportOptions, logger *slog.Logger) ReportGenerator {
+	return &HTMLReportService{
+		options: options,
+		logger:  logger,
+	}
+}
+
+func (r *HTMLReportService) GenerateReport(race *models.Race) (string, error) {
+	var charts []ScopeCharts
+	if r.options.Charts {
+		charts = RaceCharts(race, r.options)
+	}
+	return r.render(BuildRaceResult(race, r.options), charts)
+}
+
+// RenderResult выводит загруженный результат; графики строятся только по событиям гонки.
+func (r *HTMLReportService) RenderResult(result *RaceResult) (string, error) {
+	return r.render(result, nil)
+}
+
+func (r *HTMLReportService) render(result *RaceResult, charts []ScopeCharts) (string, error) {
+	catalog := r.options.Catalog
+	data := htmlReportData{
+		Lang:   catalog.Lang(),
+		Title:  catalog.T("report.results"),
+		Result: result,
+		Charts: charts,
+	}
+
+	tmpl, err := resultsHTML.Clone()
+	if err != nil {
+		return "", fmt.Errorf("failed to clone html template: %w", err)
+	}
+	tmpl.Funcs(template.FuncMap{
+		"t":          catalog.T,
+		"scopeTitle": func(scope string) string { return scopeTitle(catalog, scope) },
+		"position":   func(position string) string { return positionLabel(catalog, position) },
+	})
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render html report: %w", err)
+	}
+	return sb.String(), nil
+}
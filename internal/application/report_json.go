@@ -0,0 +1,33 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"log/slog"
+)
+
+// JSONReportService выводит структурированный результат гонки (см. RaceResult) в JSON
+type JSONReportService struct {
+	options ReportOptions
+	logger  *slog.Logger
+}
+
+func NewJSONReportService(options ReportOptions, logger *slog.Logger) ReportGenerator {
+	return &JSONReportService{
+		options: options,
+		logger:  logger,
+	}
+}
+
+func (r *JSONReportService) GenerateReport(race *models.Race) (string, error) {
+	return r.RenderResult(BuildRaceResult(race, r.options))
+}
+
+func (r *JSONReportService) RenderResult(result *RaceResult) (string, error) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode results: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,96 @@
+package application
+
+import (
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"github.com/BiathlonRaceProto-Yadro/internal/i18n"
+	"strings"
+	"text/tabwriter"
+)
+
+// Заголовок протокола: зачёт (категория) и статус, если жюри его объявляло
+func resultsTitle(catalog *i18n.Catalog, state string, scope string) string {
+	title := catalog.T("report.results")
+	if scope != "" {
+		title += " - " + scopeTitle(catalog, scope)
+	}
+	switch state {
+	case models.ResultsProvisional:
+		title += " (" + catalog.T("report.provisional") + ")"
+	case models.ResultsOfficial:
+		title += " (" + catalog.T("report.official") + ")"
+	}
+	return title
+}
+
+// Журнал решений жюри в порядке их принятия
+func (r *ReportService) generateJuryReport(decisions []JuryDecision) string {
+	if len(decisions) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n" + r.options.Catalog.T("report.jury") + ":\n")
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	header, separator := tableHeader(r.options.Catalog, "col.time", "col.id", "col.action", "col.rule", "col.penalty", "col.reason")
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		r.logger.Error("failed to write header", "error", err)
+	}
+	if _, err := fmt.Fprintln(w, separator); err != nil {
+		r.logger.Error("failed to write separator", "error", err)
+	}
+
+	for _, d := range decisions {
+		id := "-"
+		if d.CompetitorID != 0 {
+			id = fmt.Sprint(d.CompetitorID)
+		}
+		action := d.Action
+		if d.State != "" {
+			action += " " + d.State
+		}
+		penalty := "-"
+		if d.Penalty != nil {
+			penalty = d.Penalty.Text
+			if d.Penalty.Ms > 0 {
+				penalty = "+" + penalty
+			}
+		}
+		rule := d.Rule
+		if rule == "" {
+			rule = "-"
+		}
+
+		row := fmt.Sprintf(
+			"%s\t%s\t%s\t%s\t%s\t%s",
+			d.Time,
+			id,
+			action,
+			rule,
+			penalty,
+			d.Reason,
+		)
+		if _, err := fmt.Fprintln(w, row); err != nil {
+			r.logger.Error("failed to write row", "error", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		r.logger.Error("failed to flush tabwriter", "error", err)
+	}
+	return sb.String()
+}
+
+// Замечания проверки данных
+func (r *ReportService) generateWarningsReport(warnings []string) string {
+	if len(warnings) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n" + r.options.Catalog.T("report.warnings") + ":\n")
+	for _, w := range warnings {
+		sb.WriteString("- " + w + "\n")
+	}
+	return sb.String()
+}
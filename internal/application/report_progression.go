@@ -0,0 +1,99 @@
+package application
+
+import (
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"github.com/BiathlonRaceProto-Yadro/pkg/utils"
+	"strings"
+	"text/tabwriter"
+)
+
+// Ключ контрольной точки, общий для всех участников
+type checkpointKey struct {
+	kind  models.CheckpointKind
+	index int
+}
+
+// progressionOrder упорядочивает контрольные точки по ходу гонки.
+// За основу берётся последовательность участника, прошедшего больше всего точек.
+func progressionOrder(competitors []*models.Competitor) []checkpointKey {
+	var longest *models.Competitor
+	for _, c := range competitors {
+		if longest == nil || len(c.Checkpoints) > len(longest.Checkpoints) {
+			longest = c
+		}
+	}
+
+	var order []checkpointKey
+	seen := make(map[checkpointKey]bool)
+	add := func(c *models.Competitor) {
+		for _, cp := range c.Checkpoints {
+			key := checkpointKey{kind: cp.Kind, index: cp.Index}
+			if !seen[key] {
+				seen[key] = true
+				order = append(order, key)
+			}
+		}
+	}
+	if longest != nil {
+		add(longest)
+	}
+	for _, c := range competitors {
+		add(c)
+	}
+	return order
+}
+
+// Места и отставания от лидера после каждого рубежа и круга.
+// Места считаются по всем участникам протокола (field), выводятся только строки competitors.
+func (r *ReportService) generateProgressionReport(field, competitors []*models.Competitor) string {
+	var sb strings.Builder
+	sb.WriteString("\n" + r.options.Catalog.T("report.progression") + ":\n")
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	header, separator := tableHeader(r.options.Catalog, "col.checkpoint", "col.rank", "col.id", "col.time", "col.gap")
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		r.logger.Error("failed to write header", "error", err)
+	}
+	if _, err := fmt.Fprintln(w, separator); err != nil {
+		r.logger.Error("failed to write separator", "error", err)
+	}
+
+	shown := competitorSet(competitors)
+	for _, key := range progressionOrder(field) {
+		var entries []timedEntry
+		for _, c := range field {
+			for _, cp := range c.Checkpoints {
+				if cp.Kind == key.kind && cp.Index == key.index {
+					entries = append(entries, timedEntry{competitor: c, elapsed: c.Elapsed(cp.Time)})
+				}
+			}
+		}
+
+		for _, e := range rankTimes(entries) {
+			if !shown[e.competitor] {
+				continue
+			}
+			gap := "-"
+			if e.gap > 0 {
+				gap = "+" + utils.FormatDuration(e.gap)
+			}
+			row := fmt.Sprintf(
+				"%s %d\t%d\t%d\t%s\t%s",
+				r.options.Catalog.T("checkpoint."+key.kind.String()),
+				key.index,
+				e.rank,
+				e.competitor.ID,
+				utils.FormatDuration(e.elapsed),
+				gap,
+			)
+			if _, err := fmt.Fprintln(w, row); err != nil {
+				r.logger.Error("failed to write row", "error", err)
+			}
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		r.logger.Error("failed to flush tabwriter", "error", err)
+	}
+	return sb.String()
+}
@@ -0,0 +1,285 @@
+package application
+
+import (
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"github.com/BiathlonRaceProto-Yadro/pkg/utils"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Накопленная статистика стрельбы по одному положению
+type positionStats struct {
+	stages    int
+	hits      int
+	rounds    int
+	misses    int
+	rangeTime time.Duration
+}
+
+func (s *positionStats) merge(o *positionStats) {
+	s.stages += o.stages
+	s.hits += o.hits
+	s.rounds += o.rounds
+	s.misses += o.misses
+	s.rangeTime += o.rangeTime
+}
+
+func (s *positionStats) accuracy() float64 {
+	if s.rounds == 0 {
+		return 0
+	}
+	return float64(s.hits) / float64(s.rounds) * 100
+}
+
+// Положения в порядке вывода
+var reportPositions = []models.ShootingPosition{models.Prone, models.Standing}
+
+func collectPositionStats(c *models.Competitor) map[models.ShootingPosition]*positionStats {
+	stats := make(map[models.ShootingPosition]*positionStats)
+	for _, s := range c.FiringLines {
+		if !s.Finished() {
+			continue
+		}
+		st, ok := stats[s.Position()]
+		if !ok {
+			st = &positionStats{}
+			stats[s.Position()] = st
+		}
+		st.stages++
+		st.hits += s.Hits()
+		st.rounds += s.Rounds()
+		st.misses += s.Missed()
+		st.rangeTime += s.RangeTime()
+	}
+	return stats
+}
+
+// Разбивка стрельбы по положениям: по каждому участнику из competitors и по всему полю (field).
+// Без завершённых рубежей раздел не выводится.
+func (r *ReportService) generatePositionReport(field, competitors []*models.Competitor) string {
+	if !hasPositionStats(competitors) {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n" + r.options.Catalog.T("report.positions") + ":\n")
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	header, separator := tableHeader(r.options.Catalog,
+		"col.id", "col.position", "col.stages", "col.hitsShots", "col.accuracy", "col.misses", "col.rangeTime")
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		r.logger.Error("failed to write header", "error", err)
+	}
+	if _, err := fmt.Fprintln(w, separator); err != nil {
+		r.logger.Error("failed to write separator", "error", err)
+	}
+
+	shown := competitorSet(competitors)
+	totals := make(map[models.ShootingPosition]*positionStats)
+	for _, c := range field {
+		stats := collectPositionStats(c)
+		for _, pos := range reportPositions {
+			st, ok := stats[pos]
+			if !ok {
+				continue
+			}
+			if shown[c] {
+				r.writePositionRow(w, fmt.Sprint(c.ID), pos, st)
+			}
+
+			total, ok := totals[pos]
+			if !ok {
+				total = &positionStats{}
+				totals[pos] = total
+			}
+			total.merge(st)
+		}
+	}
+	for _, pos := range reportPositions {
+		if st, ok := totals[pos]; ok {
+			r.writePositionRow(w, r.options.Catalog.T("label.all"), pos, st)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		r.logger.Error("failed to flush tabwriter", "error", err)
+	}
+	return sb.String()
+}
+
+// hasPositionStats сообщает, есть ли хотя бы одна строка для разбивки по положениям
+func hasPositionStats(competitors []*models.Competitor) bool {
+	for _, c := range competitors {
+		stats := collectPositionStats(c)
+		for _, pos := range reportPositions {
+			if _, ok := stats[pos]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasFinishedStage сообщает, завершил ли кто-нибудь из участников хотя бы один рубеж
+func hasFinishedStage(competitors []*models.Competitor) bool {
+	for _, c := range competitors {
+		for _, s := range c.FiringLines {
+			if s.Finished() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *ReportService) writePositionRow(w *tabwriter.Writer, who string, pos models.ShootingPosition, st *positionStats) {
+	row := fmt.Sprintf(
+		"%s\t%s\t%d\t%d/%d\t%.1f%%\t%d\t%s",
+		who,
+		positionLabel(r.options.Catalog, pos.String()),
+		st.stages,
+		st.hits,
+		st.rounds,
+		st.accuracy(),
+		st.misses,
+		utils.FormatDuration(st.rangeTime),
+	)
+	if _, err := fmt.Fprintln(w, row); err != nil {
+		r.logger.Error("failed to write row", "error", err)
+	}
+}
+
+// Время на рубеже, время до первого выстрела, время стрельбы и ритм по каждому рубежу.
+// Без завершённых рубежей раздел не выводится.
+func (r *ReportService) generateShootingTimesReport(competitors []*models.Competitor) string {
+	if !hasFinishedStage(competitors) {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n" + r.options.Catalog.T("report.shootingTimes") + ":\n")
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	header, separator := tableHeader(r.options.Catalog, "col.id", "col.stage", "col.position", "col.rangeTime",
+		"col.firstShot", "col.shootingTime", "col.avgInterval", "col.shotIntervals")
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		r.logger.Error("failed to write header", "error", err)
+	}
+	if _, err := fmt.Fprintln(w, separator); err != nil {
+		r.logger.Error("failed to write separator", "error", err)
+	}
+
+	for _, c := range competitors {
+		for i, s := range c.FiringLines {
+			if !s.Finished() {
+				continue
+			}
+			rhythm := s.Rhythm()
+			intervals := make([]string, 0, len(rhythm))
+			var sum time.Duration
+			for _, d := range rhythm {
+				intervals = append(intervals, fmt.Sprintf("%.1f", d.Seconds()))
+				sum += d
+			}
+			avg := "-"
+			if len(rhythm) > 0 {
+				avg = fmt.Sprintf("%.1f", (sum / time.Duration(len(rhythm))).Seconds())
+			}
+
+			row := fmt.Sprintf(
+				"%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s",
+				c.ID,
+				i+1,
+				positionLabel(r.options.Catalog, s.Position().String()),
+				utils.FormatDuration(s.RangeTime()),
+				utils.FormatDuration(s.TimeToFirstShot()),
+				utils.FormatDuration(s.ShootingTime()),
+				avg,
+				strings.Join(intervals, ", "),
+			)
+			if _, err := fmt.Fprintln(w, row); err != nil {
+				r.logger.Error("failed to write row", "error", err)
+			}
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		r.logger.Error("failed to flush tabwriter", "error", err)
+	}
+	return sb.String()
+}
+
+// Аналитика стрельбы: по рубежам, установкам, номерам мишеней и спортсменам
+func (r *ReportService) generateShootingStatsReport(stats *ShootingStats) string {
+	var sb strings.Builder
+	all := r.options.Catalog.T("label.all")
+	sb.WriteString("\n" + r.options.Catalog.T("report.shootingStats") + ":\n")
+
+	var rows []string
+	for _, s := range stats.Stages {
+		rows = append(rows, shootingSummaryRow(fmt.Sprint(s.Number), s))
+	}
+	rows = append(rows, shootingSummaryRow(all, stats.Field))
+	r.writeStatsTable(&sb, rows, "col.stage", "col.stages", "col.clean", "col.hitsShots", "col.accuracy", "col.misses", "col.missRate")
+
+	rows = nil
+	for _, s := range stats.Ranges {
+		rows = append(rows, shootingSummaryRow(fmt.Sprint(s.Number), s))
+	}
+	sb.WriteString("\n")
+	r.writeStatsTable(&sb, rows, "col.range", "col.stages", "col.clean", "col.hitsShots", "col.accuracy", "col.misses", "col.missRate")
+
+	rows = nil
+	for _, t := range stats.Targets {
+		rows = append(rows, fmt.Sprintf("%d\t%d\t%d\t%d\t%.1f%%", t.Target, t.Shots, t.Hits, t.Misses, t.HitRate))
+	}
+	sb.WriteString("\n")
+	r.writeStatsTable(&sb, rows, "col.target", "col.shots", "col.hits", "col.misses", "col.hitRate")
+
+	rows = nil
+	for _, a := range stats.Athletes {
+		who := fmt.Sprint(a.ID)
+		if a.Name != "" {
+			who += " " + a.Name
+		}
+		rows = append(rows, fmt.Sprintf("%s\t%.1f%%", shootingSummaryRow(who, a.ShootingSummary), a.FieldMissShare))
+	}
+	rows = append(rows, shootingSummaryRow(all, stats.Field)+"\t100.0%")
+	sb.WriteString("\n")
+	r.writeStatsTable(&sb, rows, "col.id", "col.stages", "col.clean", "col.hitsShots", "col.accuracy", "col.misses", "col.missRate", "col.shareOfMisses")
+	return sb.String()
+}
+
+// writeStatsTable выводит таблицу с разделителем под заголовком; columns - ключи заголовков колонок
+func (r *ReportService) writeStatsTable(sb *strings.Builder, rows []string, columns ...string) {
+	w := tabwriter.NewWriter(sb, 0, 0, 2, ' ', 0)
+	header, separator := tableHeader(r.options.Catalog, columns...)
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		r.logger.Error("failed to write header", "error", err)
+	}
+	if _, err := fmt.Fprintln(w, separator); err != nil {
+		r.logger.Error("failed to write separator", "error", err)
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(w, row); err != nil {
+			r.logger.Error("failed to write row", "error", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		r.logger.Error("failed to flush tabwriter", "error", err)
+	}
+}
+
+func shootingSummaryRow(who string, s ShootingSummary) string {
+	return fmt.Sprintf(
+		"%s\t%d\t%d\t%d/%d\t%.1f%%\t%d\t%.1f%%",
+		who,
+		s.Stages,
+		s.CleanStages,
+		s.Hits,
+		s.Rounds,
+		s.Accuracy,
+		s.Misses,
+		s.MissRate,
+	)
+}
@@ -0,0 +1,70 @@
+package application
+
+import (
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"github.com/BiathlonRaceProto-Yadro/pkg/utils"
+	"strings"
+	"text/tabwriter"
+)
+
+// Промежуточное время по всем отсечкам: время гонки, место на отсечке и отставание от лучшего.
+// Места считаются по всем участникам протокола (field), выводятся только строки competitors.
+func (r *ReportService) generateSplitReport(field, competitors []*models.Competitor, course *models.Config) string {
+	if len(course.Splits) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n" + r.options.Catalog.T("report.splits") + ":\n")
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	header, separator := tableHeader(r.options.Catalog, "col.lap", "col.split", "col.distance", "col.rank", "col.id", "col.time", "col.gap")
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		r.logger.Error("failed to write header", "error", err)
+	}
+	if _, err := fmt.Fprintln(w, separator); err != nil {
+		r.logger.Error("failed to write separator", "error", err)
+	}
+
+	shown := competitorSet(competitors)
+	for lap := 1; lap <= course.Laps; lap++ {
+		for _, sp := range course.Splits {
+			var entries []timedEntry
+			for _, c := range field {
+				for _, st := range c.Splits {
+					if st.Lap == lap && st.SplitID == sp.ID {
+						entries = append(entries, timedEntry{competitor: c, elapsed: c.Elapsed(st.Time)})
+					}
+				}
+			}
+
+			for _, e := range rankTimes(entries) {
+				if !shown[e.competitor] {
+					continue
+				}
+				gap := "-"
+				if e.gap > 0 {
+					gap = "+" + utils.FormatDuration(e.gap)
+				}
+				row := fmt.Sprintf(
+					"%d\t%s\t%d\t%d\t%d\t%s\t%s",
+					lap,
+					sp.ID,
+					(lap-1)*course.LapLen+sp.Distance,
+					e.rank,
+					e.competitor.ID,
+					utils.FormatDuration(e.elapsed),
+					gap,
+				)
+				if _, err := fmt.Fprintln(w, row); err != nil {
+					r.logger.Error("failed to write row", "error", err)
+				}
+			}
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		r.logger.Error("failed to flush tabwriter", "error", err)
+	}
+	return sb.String()
+}
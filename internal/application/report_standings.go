@@ -0,0 +1,124 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/i18n"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+)
+
+type StandingsReportService struct {
+	options ReportOptions
+	logger  *slog.Logger
+}
+
+func NewStandingsReportService(options ReportOptions, logger *slog.Logger) *StandingsReportService {
+	return &StandingsReportService{
+		options: options,
+		logger:  logger,
+	}
+}
+
+// GenerateReport выводит зачёты в коротком или полном (табличном) формате, как протоколы гонок
+func (r *StandingsReportService) GenerateReport(name string, standings []*Standings) string {
+	var parts []string
+	for _, s := range standings {
+		if r.logger.Enabled(context.Background(), slog.LevelDebug) {
+			r.logger.Debug("Generating standings", "discipline", s.Discipline, "athletesCount", len(s.Entries))
+		}
+		if r.options.FullOutput {
+			parts = append(parts, r.generateFullStandings(name, s))
+		} else {
+			parts = append(parts, r.generateShortStandings(name, s))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func standingsTitle(catalog *i18n.Catalog, name string, s *Standings) string {
+	title := catalog.T("report.standings")
+	if name != "" {
+		title = name + " " + title
+	}
+	if s.Discipline != "" {
+		title += " - " + s.Discipline
+	}
+	return title + ":\n"
+}
+
+// Короткий зачёт: место, спортсмен, сумма очков и очки по гонкам (отброшенные - в скобках)
+func (r *StandingsReportService) generateShortStandings(name string, s *Standings) string {
+	var sb strings.Builder
+	sb.WriteString(standingsTitle(r.options.Catalog, name, s))
+	for _, e := range s.Entries {
+		athlete := e.Athlete
+		if e.Nation != "" {
+			athlete += " (" + e.Nation + ")"
+		}
+		points := make([]string, len(e.Results))
+		for i, res := range e.Results {
+			points[i] = formatRacePoints(res, false)
+		}
+		sb.WriteString(fmt.Sprintf("[%d] %s %d {%s}\n", e.Rank, athlete, e.Points, strings.Join(points, ", ")))
+	}
+	return sb.String()
+}
+
+// Полный зачёт: таблица с очками и местами в каждой гонке
+func (r *StandingsReportService) generateFullStandings(name string, s *Standings) string {
+	var sb strings.Builder
+	sb.WriteString(standingsTitle(r.options.Catalog, name, s))
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+
+	msg := r.options.Catalog
+	header := []string{msg.T("col.rank"), msg.T("col.athlete"), msg.T("col.nation"), msg.T("col.points")}
+	header = append(header, s.Races...)
+	if _, err := fmt.Fprintln(w, strings.Join(header, "\t")); err != nil {
+		r.logger.Error("failed to write header", "error", err)
+	}
+	if _, err := fmt.Fprintln(w, underline(header...)); err != nil {
+		r.logger.Error("failed to write separator", "error", err)
+	}
+
+	for _, e := range s.Entries {
+		nation := e.Nation
+		if nation == "" {
+			nation = "-"
+		}
+		cells := []string{fmt.Sprint(e.Rank), e.Athlete, nation, fmt.Sprint(e.Points)}
+		for _, res := range e.Results {
+			cells = append(cells, formatRacePoints(res, true))
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(cells, "\t")); err != nil {
+			r.logger.Error("failed to write row", "error", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		r.logger.Error("failed to flush tabwriter", "error", err)
+	}
+	return sb.String()
+}
+
+// formatRacePoints: "-" - не стартовал, "(n)" - отброшенный результат, withRank добавляет место
+func formatRacePoints(res RaceScore, withRank bool) string {
+	if !res.Started {
+		if res.Dropped {
+			return "(-)"
+		}
+		return "-"
+	}
+	text := fmt.Sprint(res.Points)
+	if withRank {
+		rank := "-"
+		if res.Rank > 0 {
+			rank = fmt.Sprint(res.Rank)
+		}
+		text += "/" + rank
+	}
+	if res.Dropped {
+		text = "(" + text + ")"
+	}
+	return text
+}
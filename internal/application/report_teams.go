@@ -0,0 +1,72 @@
+package application
+
+import (
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"strings"
+	"text/tabwriter"
+)
+
+// Командный зачёт с перечнем спортсменов, принёсших команде результат
+func (r *ReportService) generateTeamReport(result *RaceResult, cl Classification) string {
+	scoring := result.Race.Teams
+	teams := cl.Teams
+	if len(teams) == 0 || scoring == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	title := r.options.Catalog.T("report.teamsNations")
+	if scoring.By == models.TeamByClub {
+		title = r.options.Catalog.T("report.teamsClubs")
+	}
+	if cl.Scope != "" {
+		title += " - " + scopeTitle(r.options.Catalog, cl.Scope)
+	}
+	sb.WriteString("\n" + title + ":\n")
+
+	score := "col.totalTime"
+	if scoring.Scoring == models.TeamScoringPoints {
+		score = "col.points"
+	}
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	header, separator := tableHeader(r.options.Catalog, "col.rank", "col.team", score, "col.finishers", "col.athletes")
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		r.logger.Error("failed to write header", "error", err)
+	}
+	if _, err := fmt.Fprintln(w, separator); err != nil {
+		r.logger.Error("failed to write separator", "error", err)
+	}
+
+	for _, t := range teams {
+		rank, total := "-", "-"
+		if t.Rank > 0 {
+			rank = fmt.Sprint(t.Rank)
+			total = fmt.Sprint(t.Points)
+			if t.TotalTime != nil {
+				total = t.TotalTime.Text
+			}
+		}
+
+		athletes := make([]string, len(t.Athletes))
+		for i, m := range t.Athletes {
+			contribution := fmt.Sprint(m.Points)
+			if m.TotalTime != nil {
+				contribution = m.TotalTime.Text
+			}
+			athletes[i] = fmt.Sprintf("%s (%d, %s)", m.Name, m.Rank, contribution)
+		}
+		if len(athletes) == 0 {
+			athletes = []string{"-"}
+		}
+
+		row := fmt.Sprintf("%s\t%s\t%s\t%d\t%s", rank, t.Team, total, t.Finishers, strings.Join(athletes, "; "))
+		if _, err := fmt.Fprintln(w, row); err != nil {
+			r.logger.Error("failed to write row", "error", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		r.logger.Error("failed to flush tabwriter", "error", err)
+	}
+	return sb.String()
+}
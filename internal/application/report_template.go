@@ -0,0 +1,246 @@
+package application
+
+import (
+	_ "embed"
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"github.com/BiathlonRaceProto-Yadro/internal/i18n"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/short.txt.tmpl
+var shortReportTemplate string
+
+//go:embed templates/full.txt.tmpl
+var fullReportTemplate string
+
+// Встроенные шаблоны протокола
+var (
+	shortTemplate = template.Must(newReportTemplate("short").Parse(shortReportTemplate))
+	fullTemplate  = template.Must(newReportTemplate("full").Parse(fullReportTemplate))
+)
+
+// TemplateData - данные шаблона протокола. Шаблон выполняется для каждого протокола
+// (вся гонка, категория или общий зачёт) вместо встроенной таблицы результатов.
+type TemplateData struct {
+	Result         *RaceResult    // Весь результат: сведения о гонке, решения жюри, замечания
+	Classification Classification // Текущий протокол
+	NetLaps        bool           // Запрошено чистое время кругов (-netLaps)
+	FullOutput     bool           // Запрошен полный отчёт (-fullOutput)
+}
+
+// templateFuncs - функции шаблонов; t, resultsTitle и statusLabel выводят сообщения каталога,
+// statusLabel учитывает подписи статусов из конфигурации
+func templateFuncs(config *models.Config, catalog *i18n.Catalog) template.FuncMap {
+	return template.FuncMap{
+		"join":      strings.Join,
+		"duration":  formatTemplateDuration,
+		"ms":        durationMs,
+		"speed":     formatSpeedValue,
+		"rank":      formatRank,
+		"t":         catalog.T,
+		"underline": underline,
+		"resultsTitle": func(state, scope string) string {
+			return resultsTitle(catalog, state, scope)
+		},
+		"statusLabel": func(code string) string {
+			return statusLabel(config, catalog, models.StatusCode(code))
+		},
+		"hasAthletes":         hasAthletes,
+		"finishedLaps":        finishedLaps,
+		"finishedPenaltyLaps": finishedPenaltyLaps,
+		"penaltyLoops":        penaltyLoops,
+		"penaltyTime":         penaltyTime,
+		"penaltySpeed":        penaltySpeed,
+	}
+}
+
+func newReportTemplate(name string) *template.Template {
+	return template.New(name).Funcs(templateFuncs(nil, nil))
+}
+
+// ParseReportTemplate загружает пользовательский шаблон протокола из файла и проверяет его
+// на образце протокола, чтобы ошибка в шаблоне не обрывала отчёт на середине.
+func ParseReportTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := newReportTemplate(filepath.Base(path)).Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(io.Discard, sampleTemplateData()); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// sampleTemplateData - протокол, в котором заполнены все поля, для проверки шаблона
+func sampleTemplateData() TemplateData {
+	second := newDuration(time.Second)
+	res := CompetitorResult{
+		Rank:           1,
+		ID:             1,
+		Athlete:        &AthleteInfo{Name: "Athlete", Nation: "NOR", Club: "Club", BirthYear: 2000, Category: "Senior"},
+		Category:       "Senior",
+		StatusCode:     string(models.CodeFinished),
+		Status:         statusLabel(nil, nil, models.CodeFinished),
+		Scheduled:      "10:00:00.000",
+		Start:          "10:00:00.000",
+		Finish:         "10:00:01.000",
+		LappedAt:       "10:00:01.000",
+		TotalTime:      second,
+		GapToLeader:    second,
+		TimeAdjustment: second,
+		Laps: []LapResult{{Lap: 1, Finish: "10:00:01.000", Duration: second, Speed: 1,
+			NetDuration: second, NetSpeed: 1}},
+		PenaltyLaps: []PenaltyLapResult{{Index: 1, Missed: 1, Distance: 150, Duration: second, Speed: 1}},
+		Shooting: []ShootingStage{{Stage: 1, Line: 1, Position: models.Prone.String(), Targets: 5, Rounds: 5,
+			Hits: 4, Missed: 1, RangeTime: second}},
+		Hits:                   4,
+		Shots:                  5,
+		Accuracy:               80,
+		DisqualificationReason: "reason",
+	}
+	cl := Classification{
+		Scope:   "Senior",
+		Results: []CompetitorResult{res},
+		Teams: []TeamStanding{{Rank: 1, Team: "NOR", TotalTime: second, Points: 1, Finishers: 1,
+			Athletes: []TeamMember{{ID: 1, Name: "Athlete", Rank: 1, TotalTime: second, Points: 1}}}},
+		ShootingStats: BuildShootingStats(nil),
+	}
+	result := &RaceResult{
+		SchemaVersion: ResultSchemaVersion,
+		Race: RaceInfo{Format: models.FormatSprint, Laps: 1, LapLen: 1, PenaltyLen: 150, FiringLines: 1,
+			ShotsPerStage: 5, Start: "10:00:00.000", StartDelta: second, Categories: []string{"Senior"},
+			Teams: &TeamRules{By: models.TeamByNation, Scoring: models.TeamScoringTime, Best: 1, MinFinishers: 1}},
+		ResultsState:    models.ResultsOfficial,
+		Classifications: []Classification{cl},
+		Jury: []JuryDecision{{Time: "10:00:01.000", Action: "Disqualified", CompetitorID: 1, Rule: "1",
+			Penalty: second, State: models.ResultsOfficial, Reason: "reason"}},
+		Warnings: []string{"warning"},
+	}
+	return TemplateData{Result: result, Classification: cl}
+}
+
+// executeReportTemplate выполняет шаблон для протокола; колонки, разделённые табуляцией, выравниваются.
+// Ошибка выполнения (например, обращение к полю отсутствующего результата) возвращается,
+// а не обрывает протокол на середине.
+func (r *ReportService) executeReportTemplate(tmpl *template.Template, result *RaceResult, cl Classification) (string, error) {
+	t, err := tmpl.Clone()
+	if err != nil {
+		return "", fmt.Errorf("failed to clone report template: %w", err)
+	}
+	t.Funcs(templateFuncs(r.config, r.options.Catalog))
+
+	data := TemplateData{
+		Result:         result,
+		Classification: cl,
+		NetLaps:        r.options.NetLaps,
+		FullOutput:     r.options.FullOutput,
+	}
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	if err := t.Execute(w, data); err != nil {
+		return "", fmt.Errorf("failed to render report template %s: %w", t.Name(), err)
+	}
+	if err := w.Flush(); err != nil {
+		r.logger.Error("failed to flush tabwriter", "error", err)
+	}
+	return sb.String(), nil
+}
+
+// formatTemplateDuration - время или "-", если его нет
+func formatTemplateDuration(d *Duration) string {
+	if d == nil {
+		return "-"
+	}
+	return d.Text
+}
+
+// formatRank - место или "-" для участников без результата
+func formatRank(rank int) string {
+	if rank == 0 {
+		return "-"
+	}
+	return fmt.Sprint(rank)
+}
+
+// hasAthletes сообщает, привязан ли к реестру хотя бы один участник протокола
+func hasAthletes(cl Classification) bool {
+	for _, res := range cl.Results {
+		if res.Athlete != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// finishedLaps - завершённые основные круги
+func finishedLaps(laps []LapResult) []LapResult {
+	var finished []LapResult
+	for _, lap := range laps {
+		if lap.Duration != nil {
+			finished = append(finished, lap)
+		}
+	}
+	return finished
+}
+
+// finishedPenaltyLaps - завершённые штрафные круги
+func finishedPenaltyLaps(laps []PenaltyLapResult) []PenaltyLapResult {
+	var finished []PenaltyLapResult
+	for _, lap := range laps {
+		if lap.Duration != nil {
+			finished = append(finished, lap)
+		}
+	}
+	return finished
+}
+
+// penaltyLoops - число пройденных штрафных кругов: по кругу за каждый промах.
+// Один заход на штрафную петлю может включать несколько кругов.
+func penaltyLoops(res CompetitorResult) int {
+	loops := 0
+	for _, lap := range res.PenaltyLaps {
+		loops += lap.Missed
+	}
+	return loops
+}
+
+// penaltyTime - суммарное время на штрафных кругах; nil, если их не было
+func penaltyTime(res CompetitorResult) *Duration {
+	var total int64
+	for _, lap := range res.PenaltyLaps {
+		if lap.Duration != nil {
+			total += lap.Duration.Ms
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	return newDuration(time.Duration(total) * time.Millisecond)
+}
+
+// penaltySpeed - средняя скорость на штрафных кругах, м/с
+func penaltySpeed(res CompetitorResult) float64 {
+	var total int64
+	var distance int
+	for _, lap := range res.PenaltyLaps {
+		if lap.Duration != nil {
+			total += lap.Duration.Ms
+			distance += lap.Distance
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(distance) / (time.Duration(total) * time.Millisecond).Seconds()
+}
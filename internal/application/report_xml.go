@@ -0,0 +1,33 @@
+package application
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"log/slog"
+)
+
+// XMLReportService выводит структурированный результат гонки в XML по схеме schema/results-1.0.xsd
+type XMLReportService struct {
+	options ReportOptions
+	logger  *slog.Logger
+}
+
+func NewXMLReportService(options ReportOptions, logger *slog.Logger) ReportGenerator {
+	return &XMLReportService{
+		options: options,
+		logger:  logger,
+	}
+}
+
+func (r *XMLReportService) GenerateReport(race *models.Race) (string, error) {
+	return r.RenderResult(BuildRaceResult(race, r.options))
+}
+
+func (r *XMLReportService) RenderResult(result *RaceResult) (string, error) {
+	data, err := xml.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode results: %w", err)
+	}
+	return xml.Header + string(data), nil
+}
@@ -0,0 +1,370 @@
+package application
+
+import (
+	"encoding/xml"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"github.com/BiathlonRaceProto-Yadro/pkg/utils"
+	"math"
+	"time"
+)
+
+// ResultSchemaVersion - версия схемы структурированного результата.
+// Меняется при несовместимых изменениях полей; новые необязательные поля версию не меняют.
+const ResultSchemaVersion = "1.0"
+
+// Duration - длительность в миллисекундах и в формате HH:MM:SS.sss
+type Duration struct {
+	Ms   int64  `json:"ms" xml:"ms,attr"`
+	Text string `json:"text" xml:",chardata"`
+}
+
+func newDuration(d time.Duration) *Duration {
+	text := utils.FormatDuration(d)
+	if d < 0 {
+		text = "-" + utils.FormatDuration(-d)
+	}
+	return &Duration{Ms: d.Milliseconds(), Text: text}
+}
+
+// RaceResult - структурированный результат гонки, не зависящий от формата вывода
+type RaceResult struct {
+	XMLName         xml.Name         `json:"-" xml:"raceResult"`
+	SchemaVersion   string           `json:"schemaVersion" xml:"schemaVersion,attr"`
+	Race            RaceInfo         `json:"race" xml:"race"`
+	ResultsState    string           `json:"resultsState,omitempty" xml:"resultsState,attr,omitempty"` // provisional, official
+	Classifications []Classification `json:"classifications" xml:"classification"`
+	Jury            []JuryDecision   `json:"jury,omitempty" xml:"jury>decision,omitempty"`
+	Warnings        []string         `json:"warnings,omitempty" xml:"warnings>warning,omitempty"`
+}
+
+// RaceInfo - параметры гонки из конфигурации
+type RaceInfo struct {
+	Format        string     `json:"format" xml:"format,attr"`
+	Laps          int        `json:"laps" xml:"laps,attr"`
+	LapLen        int        `json:"lapLen" xml:"lapLen,attr"`
+	PenaltyLen    int        `json:"penaltyLen" xml:"penaltyLen,attr"`
+	FiringLines   int        `json:"firingLines" xml:"firingLines,attr"`
+	ShotsPerStage int        `json:"shotsPerStage" xml:"shotsPerStage,attr"`
+	SpareRounds   int        `json:"spareRounds" xml:"spareRounds,attr"`
+	Start         string     `json:"start" xml:"start,attr"`
+	StartDelta    *Duration  `json:"startDelta" xml:"startDelta"`
+	Categories    []string   `json:"categories,omitempty" xml:"category,omitempty"`
+	Teams         *TeamRules `json:"teams,omitempty" xml:"teams,omitempty"`
+}
+
+// TeamRules - правила командного зачёта
+type TeamRules struct {
+	By           string `json:"by" xml:"by,attr"`
+	Scoring      string `json:"scoring" xml:"scoring,attr"`
+	Best         int    `json:"best" xml:"best,attr"`
+	MinFinishers int    `json:"minFinishers" xml:"minFinishers,attr"`
+}
+
+// Classification - протокол гонки, категории или общего зачёта
+type Classification struct {
+	Scope   string             `json:"scope,omitempty" xml:"scope,attr,omitempty"`
+	Results []CompetitorResult `json:"results" xml:"competitor"`
+	Teams   []TeamStanding     `json:"teams,omitempty" xml:"teams>team,omitempty"`
+
+	ShootingStats *ShootingStats `json:"shootingStats,omitempty" xml:"shootingStats,omitempty"`
+}
+
+// CompetitorResult - строка протокола
+type CompetitorResult struct {
+	Rank                   int                `json:"rank,omitempty" xml:"rank,attr,omitempty"`
+	ID                     int                `json:"id" xml:"id,attr"`
+	Athlete                *AthleteInfo       `json:"athlete,omitempty" xml:"athlete,omitempty"`
+	Category               string             `json:"category,omitempty" xml:"category,attr,omitempty"`
+	StatusCode             string             `json:"statusCode" xml:"statusCode,attr"`
+	Status                 string             `json:"status" xml:"status,attr"`
+	Scheduled              string             `json:"scheduled,omitempty" xml:"scheduled,attr,omitempty"`
+	Start                  string             `json:"start,omitempty" xml:"start,attr,omitempty"`
+	Finish                 string             `json:"finish,omitempty" xml:"finish,attr,omitempty"`
+	LappedAt               string             `json:"lappedAt,omitempty" xml:"lappedAt,attr,omitempty"` // Когда участника обогнали на круг (lappedRule)
+	TotalTime              *Duration          `json:"totalTime,omitempty" xml:"totalTime,omitempty"`
+	GapToLeader            *Duration          `json:"gapToLeader,omitempty" xml:"gapToLeader,omitempty"`
+	TimeAdjustment         *Duration          `json:"timeAdjustment,omitempty" xml:"timeAdjustment,omitempty"`
+	Laps                   []LapResult        `json:"laps" xml:"laps>lap"`
+	PenaltyLaps            []PenaltyLapResult `json:"penaltyLaps" xml:"penaltyLaps>penaltyLap"`
+	Shooting               []ShootingStage    `json:"shooting" xml:"shooting>stage"`
+	Hits                   int                `json:"hits" xml:"hits,attr"`
+	Shots                  int                `json:"shots" xml:"shots,attr"`
+	Accuracy               float64            `json:"accuracy" xml:"accuracy,attr"` // Процент попаданий
+	DisqualificationReason string             `json:"disqualificationReason,omitempty" xml:"disqualificationReason,omitempty"`
+}
+
+// AthleteInfo - данные спортсмена из реестра
+type AthleteInfo struct {
+	Name      string `json:"name" xml:"name,attr"`
+	Nation    string `json:"nation,omitempty" xml:"nation,attr,omitempty"`
+	Club      string `json:"club,omitempty" xml:"club,attr,omitempty"`
+	BirthYear int    `json:"birthYear,omitempty" xml:"birthYear,attr,omitempty"`
+	Category  string `json:"category,omitempty" xml:"category,attr,omitempty"`
+}
+
+// LapResult - основной круг; для незавершённого круга время и скорость не заполняются
+type LapResult struct {
+	Lap         int       `json:"lap" xml:"number,attr"`
+	Finish      string    `json:"finish,omitempty" xml:"finish,attr,omitempty"`
+	Duration    *Duration `json:"duration,omitempty" xml:"duration,omitempty"`
+	Speed       float64   `json:"speed,omitempty" xml:"speed,attr,omitempty"` // м/с
+	NetDuration *Duration `json:"netDuration,omitempty" xml:"netDuration,omitempty"`
+	NetSpeed    float64   `json:"netSpeed,omitempty" xml:"netSpeed,attr,omitempty"`
+}
+
+// PenaltyLapResult - штрафные круги после одного рубежа
+type PenaltyLapResult struct {
+	Index    int       `json:"index" xml:"index,attr"`
+	Missed   int       `json:"missed" xml:"missed,attr"`
+	Distance int       `json:"distance" xml:"distance,attr"` // м
+	Duration *Duration `json:"duration,omitempty" xml:"duration,omitempty"`
+	Speed    float64   `json:"speed,omitempty" xml:"speed,attr,omitempty"`
+}
+
+// ShootingStage - стрельба на одном рубеже
+type ShootingStage struct {
+	Stage     int       `json:"stage" xml:"number,attr"`
+	Line      int       `json:"line" xml:"line,attr"`
+	Position  string    `json:"position,omitempty" xml:"position,attr,omitempty"`
+	Targets   int       `json:"targets" xml:"targets,attr"`
+	Rounds    int       `json:"rounds" xml:"rounds,attr"`
+	Hits      int       `json:"hits" xml:"hits,attr"`
+	Missed    int       `json:"missed" xml:"missed,attr"`
+	RangeTime *Duration `json:"rangeTime,omitempty" xml:"rangeTime,omitempty"`
+}
+
+// TeamStanding - строка командного зачёта
+type TeamStanding struct {
+	Rank      int          `json:"rank,omitempty" xml:"rank,attr,omitempty"`
+	Team      string       `json:"team" xml:"name,attr"`
+	TotalTime *Duration    `json:"totalTime,omitempty" xml:"totalTime,omitempty"`
+	Points    int          `json:"points,omitempty" xml:"points,attr,omitempty"`
+	Finishers int          `json:"finishers" xml:"finishers,attr"`
+	Athletes  []TeamMember `json:"athletes" xml:"athlete"`
+}
+
+// TeamMember - спортсмен, идущий в командный зачёт
+type TeamMember struct {
+	ID        int       `json:"id" xml:"id,attr"`
+	Name      string    `json:"name" xml:"name,attr"`
+	Rank      int       `json:"rank" xml:"rank,attr"`
+	TotalTime *Duration `json:"totalTime,omitempty" xml:"totalTime,omitempty"`
+	Points    int       `json:"points,omitempty" xml:"points,attr,omitempty"`
+}
+
+// JuryDecision - решение жюри
+type JuryDecision struct {
+	Time         string    `json:"time" xml:"time,attr"`
+	Action       string    `json:"action" xml:"action,attr"`
+	CompetitorID int       `json:"competitorId,omitempty" xml:"competitorId,attr,omitempty"`
+	Rule         string    `json:"rule,omitempty" xml:"rule,attr,omitempty"`
+	Penalty      *Duration `json:"penalty,omitempty" xml:"penalty,omitempty"`
+	State        string    `json:"state,omitempty" xml:"state,attr,omitempty"`
+	Reason       string    `json:"reason,omitempty" xml:"reason,omitempty"`
+}
+
+// BuildRaceResult собирает структурированный результат гонки с теми же протоколами,
+// фильтром и правилами ранжирования, что и текстовый отчёт.
+func BuildRaceResult(race *models.Race, options ReportOptions) *RaceResult {
+	cfg := race.Config
+	result := &RaceResult{
+		SchemaVersion: ResultSchemaVersion,
+		Race: RaceInfo{
+			Format:        cfg.Format,
+			Laps:          cfg.Laps,
+			LapLen:        cfg.LapLen,
+			PenaltyLen:    cfg.PenaltyLen,
+			FiringLines:   cfg.FiringLines,
+			ShotsPerStage: cfg.ShotsPerStage,
+			SpareRounds:   cfg.SpareRounds,
+			Start:         utils.FormatTimestamp(cfg.Start),
+			StartDelta:    newDuration(cfg.StartDelta),
+		},
+		Classifications: []Classification{},
+		Warnings:        race.Warnings,
+	}
+	for _, cat := range cfg.Categories {
+		result.Race.Categories = append(result.Race.Categories, cat.Name)
+	}
+	if t := cfg.Teams; t != nil {
+		result.Race.Teams = &TeamRules{By: t.By, Scoring: t.Scoring, Best: t.Best, MinFinishers: t.MinFinishers}
+	}
+
+	if race.Jury != nil {
+		result.ResultsState = race.Jury.State
+		for _, a := range race.Jury.Actions {
+			result.Jury = append(result.Jury, buildJuryDecision(a))
+		}
+	}
+
+	for _, scope := range raceScopes(race, options.Overall) {
+		ranked := RankCompetitors(scope.members)
+		classification := Classification{Scope: scope.name, Results: []CompetitorResult{}}
+
+		var leader time.Duration
+		if len(ranked) > 0 && ranked[0].Competitor.Status == models.Finished {
+			leader = ranked[0].Competitor.TotalTime()
+		}
+		rows := options.Filter.Apply(ranked)
+		for _, row := range rows {
+			res := buildCompetitorResult(row, cfg, options)
+			if row.Competitor.Status == models.Finished && leader > 0 {
+				res.GapToLeader = newDuration(row.Competitor.TotalTime() - leader)
+			}
+			classification.Results = append(classification.Results, res)
+		}
+		if options.ShootingStats {
+			classification.ShootingStats = BuildShootingStats(rowCompetitors(rows))
+		}
+		if cfg.Teams != nil {
+			classification.Teams = buildTeamStandings(ClassifyTeams(ranked, cfg.Teams), cfg.Teams)
+		}
+		result.Classifications = append(result.Classifications, classification)
+	}
+	return result
+}
+
+func buildCompetitorResult(row ResultRow, cfg *models.Config, options ReportOptions) CompetitorResult {
+	c := row.Competitor
+	course := cfg.CourseFor(c.Category)
+	res := CompetitorResult{
+		Rank:                   row.Rank,
+		ID:                     c.ID,
+		Category:               c.Category,
+		StatusCode:             string(c.StatusCode()),
+		Status:                 statusLabel(cfg, options.Catalog, c.StatusCode()),
+		Scheduled:              formatOptionalTimestamp(c.Scheduled),
+		Start:                  formatOptionalTimestamp(c.ActualStart),
+		Finish:                 formatOptionalTimestamp(c.FinishTime),
+		LappedAt:               formatOptionalTimestamp(c.LappedAt),
+		Laps:                   []LapResult{},
+		PenaltyLaps:            []PenaltyLapResult{},
+		Shooting:               []ShootingStage{},
+		Hits:                   c.Hits,
+		Shots:                  c.Shots,
+		Accuracy:               round3(c.Accuracy()),
+		DisqualificationReason: c.DisqualificationReason,
+	}
+	if c.Athlete != nil {
+		res.Athlete = &AthleteInfo{
+			Name:      c.Athlete.Name,
+			Nation:    c.Athlete.Nation,
+			Club:      c.Athlete.Club,
+			BirthYear: c.Athlete.BirthYear,
+			Category:  c.Athlete.Category,
+		}
+	}
+	if d := c.TotalTime(); d > 0 {
+		res.TotalTime = newDuration(d)
+	}
+	if c.TimeAdjustment != 0 {
+		res.TimeAdjustment = newDuration(c.TimeAdjustment)
+	}
+
+	for i, lap := range c.MainLaps() {
+		lr := LapResult{Lap: i + 1}
+		if !lap.Finish.IsZero() {
+			dur := lap.Finish.Sub(lap.Start)
+			net := c.NetLapDuration(lap, options.ExcludeRange)
+			lr.Finish = utils.FormatTimestamp(lap.Finish)
+			lr.Duration = newDuration(dur)
+			lr.Speed = speed(course.LapLen, dur)
+			lr.NetDuration = newDuration(net)
+			lr.NetSpeed = speed(course.LapLen, net)
+		}
+		res.Laps = append(res.Laps, lr)
+	}
+
+	missed := c.PenaltyMissedShots()
+	for i, lap := range c.PenaltyLaps() {
+		pr := PenaltyLapResult{Index: i + 1}
+		if i < len(missed) {
+			pr.Missed = missed[i]
+			pr.Distance = missed[i] * course.PenaltyLen
+		}
+		if !lap.Finish.IsZero() {
+			dur := lap.Finish.Sub(lap.Start)
+			pr.Duration = newDuration(dur)
+			pr.Speed = speed(pr.Distance, dur)
+		}
+		res.PenaltyLaps = append(res.PenaltyLaps, pr)
+	}
+
+	for i, s := range c.FiringLines {
+		stage := ShootingStage{
+			Stage:   i + 1,
+			Line:    s.Line(),
+			Targets: s.Targets(),
+			Rounds:  s.Rounds(),
+			Hits:    s.Hits(),
+			Missed:  s.Missed(),
+		}
+		if s.Position() != "" {
+			stage.Position = s.Position().String()
+		}
+		if s.Finished() {
+			stage.RangeTime = newDuration(s.RangeTime())
+		}
+		res.Shooting = append(res.Shooting, stage)
+	}
+	return res
+}
+
+func buildTeamStandings(teams []TeamResult, scoring *models.TeamScoring) []TeamStanding {
+	standings := make([]TeamStanding, 0, len(teams))
+	for _, t := range teams {
+		ts := TeamStanding{Rank: t.Rank, Team: t.Team, Finishers: t.Finishers, Athletes: []TeamMember{}}
+		if t.Rank > 0 {
+			if scoring.Scoring == models.TeamScoringPoints {
+				ts.Points = t.Points
+			} else {
+				ts.TotalTime = newDuration(t.Time)
+			}
+		}
+		for _, m := range t.Members {
+			member := TeamMember{ID: m.Competitor.ID, Name: m.Competitor.Athlete.Name, Rank: m.Rank}
+			if scoring.Scoring == models.TeamScoringPoints {
+				member.Points = scoring.PointsFor(m.Rank)
+			} else {
+				member.TotalTime = newDuration(m.Competitor.TotalTime())
+			}
+			ts.Athletes = append(ts.Athletes, member)
+		}
+		standings = append(standings, ts)
+	}
+	return standings
+}
+
+func buildJuryDecision(a models.JuryAction) JuryDecision {
+	d := JuryDecision{
+		Time:         utils.FormatTimestamp(a.Time),
+		Action:       a.Type.String(),
+		CompetitorID: a.CompetitorID,
+		Rule:         a.Rule,
+		State:        a.State,
+		Reason:       a.Reason,
+	}
+	if a.Penalty != 0 {
+		d.Penalty = newDuration(a.Penalty)
+	}
+	return d
+}
+
+func formatOptionalTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return utils.FormatTimestamp(t)
+}
+
+// speed - скорость в м/с, округлённая до тысячных, как в текстовом отчёте
+func speed(distance int, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return round3(float64(distance) / d.Seconds())
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
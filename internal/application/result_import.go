@@ -0,0 +1,76 @@
+package application
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// ParseResultXML загружает результат, сохранённый с -format xml.
+func ParseResultXML(data []byte) (*RaceResult, error) {
+	var result RaceResult
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid results xml: %w", err)
+	}
+	return normalizeResult(&result)
+}
+
+// ParseResultJSON загружает результат, сохранённый с -format json.
+func ParseResultJSON(data []byte) (*RaceResult, error) {
+	var result RaceResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid results json: %w", err)
+	}
+	return normalizeResult(&result)
+}
+
+// normalizeResult проверяет версию схемы и восстанавливает пустые списки,
+// которые XML не различает с отсутствующими.
+func normalizeResult(result *RaceResult) (*RaceResult, error) {
+	major, _, _ := strings.Cut(ResultSchemaVersion, ".")
+	if resultMajor, _, _ := strings.Cut(result.SchemaVersion, "."); resultMajor != major {
+		return nil, fmt.Errorf("unsupported results schema version %q", result.SchemaVersion)
+	}
+	if result.Classifications == nil {
+		result.Classifications = []Classification{}
+	}
+	for i := range result.Classifications {
+		cl := &result.Classifications[i]
+		if cl.Results == nil {
+			cl.Results = []CompetitorResult{}
+		}
+		for j := range cl.Results {
+			res := &cl.Results[j]
+			if res.Laps == nil {
+				res.Laps = []LapResult{}
+			}
+			if res.PenaltyLaps == nil {
+				res.PenaltyLaps = []PenaltyLapResult{}
+			}
+			if res.Shooting == nil {
+				res.Shooting = []ShootingStage{}
+			}
+		}
+		for j := range cl.Teams {
+			if cl.Teams[j].Athletes == nil {
+				cl.Teams[j].Athletes = []TeamMember{}
+			}
+		}
+		if s := cl.ShootingStats; s != nil {
+			if s.Stages == nil {
+				s.Stages = []ShootingSummary{}
+			}
+			if s.Ranges == nil {
+				s.Ranges = []ShootingSummary{}
+			}
+			if s.Targets == nil {
+				s.Targets = []TargetHitRate{}
+			}
+			if s.Athletes == nil {
+				s.Athletes = []AthleteShooting{}
+			}
+		}
+	}
+	return result, nil
+}
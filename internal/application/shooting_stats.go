@@ -0,0 +1,145 @@
+package application
+
+import (
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"sort"
+)
+
+// ShootingStats - аналитика стрельбы по протоколу (учитываются только завершённые рубежи)
+type ShootingStats struct {
+	Field    ShootingSummary   `json:"field" xml:"field"`
+	Stages   []ShootingSummary `json:"stages" xml:"stages>stage"` // По номеру рубежа в гонке
+	Ranges   []ShootingSummary `json:"ranges" xml:"ranges>range"` // По номеру стрелковой установки
+	Targets  []TargetHitRate   `json:"targets" xml:"targets>target"`
+	Athletes []AthleteShooting `json:"athletes" xml:"athletes>athlete"`
+}
+
+// ShootingSummary - сводка по группе рубежей
+type ShootingSummary struct {
+	Number      int     `json:"number,omitempty" xml:"number,attr,omitempty"` // Номер рубежа или установки
+	Stages      int     `json:"stages" xml:"stages,attr"`
+	CleanStages int     `json:"cleanStages" xml:"cleanStages,attr"` // Рубежи без промахов
+	Targets     int     `json:"targets" xml:"targets,attr"`
+	Rounds      int     `json:"rounds" xml:"rounds,attr"`
+	Hits        int     `json:"hits" xml:"hits,attr"`
+	Misses      int     `json:"misses" xml:"misses,attr"`
+	Accuracy    float64 `json:"accuracy" xml:"accuracy,attr"` // Процент попаданий от произведённых выстрелов
+	MissRate    float64 `json:"missRate" xml:"missRate,attr"` // Процент непоражённых мишеней
+}
+
+// TargetHitRate - поражаемость мишени с данным номером
+type TargetHitRate struct {
+	Target  int     `json:"target" xml:"number,attr"`
+	Shots   int     `json:"shots" xml:"shots,attr"` // Сколько раз мишень была в работе
+	Hits    int     `json:"hits" xml:"hits,attr"`
+	Misses  int     `json:"misses" xml:"misses,attr"`
+	HitRate float64 `json:"hitRate" xml:"hitRate,attr"`
+}
+
+// AthleteShooting - стрельба одного спортсмена
+type AthleteShooting struct {
+	ID   int    `json:"id" xml:"id,attr"`
+	Name string `json:"name,omitempty" xml:"name,attr,omitempty"`
+	ShootingSummary
+	FieldMissShare float64 `json:"fieldMissShare" xml:"fieldMissShare,attr"` // Доля промахов спортсмена среди всех промахов протокола, %
+}
+
+// add учитывает один завершённый рубеж
+func (s *ShootingSummary) add(targets, rounds, hits, missed int) {
+	s.Stages++
+	if missed == 0 {
+		s.CleanStages++
+	}
+	s.Targets += targets
+	s.Rounds += rounds
+	s.Hits += hits
+	s.Misses += missed
+}
+
+// finish вычисляет проценты по накопленным счётчикам
+func (s *ShootingSummary) finish() {
+	s.Accuracy = percent(s.Hits, s.Rounds)
+	s.MissRate = percent(s.Misses, s.Targets)
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return round3(float64(part) / float64(total) * 100)
+}
+
+// BuildShootingStats собирает аналитику стрельбы по участникам в порядке протокола.
+func BuildShootingStats(competitors []*models.Competitor) *ShootingStats {
+	stats := &ShootingStats{
+		Stages:   []ShootingSummary{},
+		Ranges:   []ShootingSummary{},
+		Targets:  []TargetHitRate{},
+		Athletes: []AthleteShooting{},
+	}
+	stages := make(map[int]*ShootingSummary)
+	ranges := make(map[int]*ShootingSummary)
+	perTarget := make(map[int]*TargetHitRate)
+	group := func(m map[int]*ShootingSummary, key int) *ShootingSummary {
+		if m[key] == nil {
+			m[key] = &ShootingSummary{Number: key}
+		}
+		return m[key]
+	}
+
+	for _, c := range competitors {
+		athlete := AthleteShooting{ID: c.ID}
+		if c.Athlete != nil {
+			athlete.Name = c.Athlete.Name
+		}
+		for i, s := range c.FiringLines {
+			if !s.Finished() {
+				continue
+			}
+			targets, rounds, hits, missed := s.Targets(), s.Rounds(), s.Hits(), s.Missed()
+			athlete.add(targets, rounds, hits, missed)
+			stats.Field.add(targets, rounds, hits, missed)
+			group(stages, i+1).add(targets, rounds, hits, missed)
+			group(ranges, s.Line()).add(targets, rounds, hits, missed)
+			for t := 1; t <= s.Targets(); t++ {
+				rate := perTarget[t]
+				if rate == nil {
+					rate = &TargetHitRate{Target: t}
+					perTarget[t] = rate
+				}
+				rate.Shots++
+				if s.Hit(t) {
+					rate.Hits++
+				} else {
+					rate.Misses++
+				}
+			}
+		}
+		stats.Athletes = append(stats.Athletes, athlete)
+	}
+
+	stats.Field.finish()
+	for i := range stats.Athletes {
+		a := &stats.Athletes[i]
+		a.finish()
+		a.FieldMissShare = percent(a.Misses, stats.Field.Misses)
+	}
+	stats.Stages = sortedSummaries(stages)
+	stats.Ranges = sortedSummaries(ranges)
+	for _, rate := range perTarget {
+		rate.HitRate = percent(rate.Hits, rate.Shots)
+		stats.Targets = append(stats.Targets, *rate)
+	}
+	sort.Slice(stats.Targets, func(i, j int) bool { return stats.Targets[i].Target < stats.Targets[j].Target })
+	return stats
+}
+
+func sortedSummaries(m map[int]*ShootingSummary) []ShootingSummary {
+	result := make([]ShootingSummary, 0, len(m))
+	for _, s := range m {
+		s.finish()
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Number < result[j].Number })
+	return result
+}
@@ -0,0 +1,243 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// RaceScore - результат спортсмена в одной гонке зачёта
+type RaceScore struct {
+	Started bool // Спортсмен стартовал в гонке
+	Rank    int  // Место; 0 - без места
+	Points  int
+	Dropped bool // Результат не учитывается по правилу худших результатов
+}
+
+// StandingsEntry - строка зачёта
+type StandingsEntry struct {
+	Rank    int
+	Athlete string // Имя из реестра или стартовый номер
+	Nation  string
+	Points  int
+	Results []RaceScore // В порядке гонок зачёта
+}
+
+// Standings - общий зачёт или зачёт по дисциплине
+type Standings struct {
+	Discipline string   // Пусто для общего зачёта
+	Races      []string // Названия учтённых гонок
+	Entries    []StandingsEntry
+}
+
+// standingsRace - протокол гонки, учитываемый в зачёте
+type standingsRace struct {
+	name       string
+	discipline string
+	results    []CompetitorResult
+}
+
+// RunStandings обрабатывает гонки манифеста и строит общий зачёт и зачёты по дисциплинам.
+func (a *App) RunStandings(manifestPath string, options ReportOptions) (string, error) {
+	if a.logger.Enabled(context.Background(), slog.LevelDebug) {
+		a.logger.Debug("Loading championship manifest", "path", manifestPath)
+	}
+	champ, err := a.manifestLoader.LoadManifest(manifestPath)
+	if err != nil {
+		a.logger.Error("Failed to load manifest", "path", manifestPath, "error", err)
+		return "", err
+	}
+
+	// Зачёт по неполным данным вводит в заблуждение, поэтому нужна каждая гонка
+	var races []standingsRace
+	for _, o := range a.runRaces(champ.Races, options) {
+		if o.Err != nil {
+			return "", fmt.Errorf("race %q: %w", o.Entry.Name, o.Err)
+		}
+		race, err := raceForStandings(o)
+		if err != nil {
+			return "", err
+		}
+		races = append(races, race)
+	}
+
+	standings := []*Standings{buildStandings(races, champ, champ.DropWorst, "")}
+	for _, discipline := range disciplines(races) {
+		var selected []standingsRace
+		for _, r := range races {
+			if r.discipline == discipline {
+				selected = append(selected, r)
+			}
+		}
+		// Худшие результаты отбрасываются только в общем зачёте
+		standings = append(standings, buildStandings(selected, champ, 0, discipline))
+	}
+
+	a.logger.Info("Generating standings report")
+	return NewStandingsReportService(options, a.logger).GenerateReport(champ.Name, standings), nil
+}
+
+// raceForStandings выбирает протокол гонки для зачёта: категории из манифеста,
+// а для гонки с категориями без указания категории - общий зачёт.
+func raceForStandings(o RaceOutcome) (standingsRace, error) {
+	race := standingsRace{name: o.Entry.Name, discipline: o.Entry.Format}
+	if race.discipline == "" {
+		race.discipline = o.Result.Race.Format
+	}
+
+	classifications := o.Result.Classifications
+	switch {
+	case o.Entry.Category != "":
+		for _, cl := range classifications {
+			if strings.EqualFold(cl.Scope, o.Entry.Category) {
+				race.results = cl.Results
+			}
+		}
+	case len(classifications) == 1:
+		race.results = classifications[0].Results
+	default:
+		found := false
+		for _, cl := range classifications {
+			if cl.Scope == "Overall" {
+				race.results, found = cl.Results, true
+			}
+		}
+		if !found {
+			return race, fmt.Errorf("race %q: results have several classifications, set the category", o.Entry.Name)
+		}
+	}
+	return race, nil
+}
+
+// disciplines возвращает дисциплины в порядке первого появления; при одной дисциплине
+// отдельный зачёт совпадает с общим и не строится.
+func disciplines(races []standingsRace) []string {
+	var list []string
+	seen := make(map[string]bool)
+	for _, r := range races {
+		if !seen[r.discipline] {
+			seen[r.discipline] = true
+			list = append(list, r.discipline)
+		}
+	}
+	if len(list) < 2 {
+		return nil
+	}
+	return list
+}
+
+// buildStandings суммирует очки спортсменов по гонкам. Из каждой суммы исключаются dropWorst
+// худших результатов (пропущенная гонка считается нулевым результатом). При равенстве очков
+// выше тот, у кого больше побед, затем вторых мест и т.д.
+func buildStandings(races []standingsRace, champ *models.Championship, dropWorst int, discipline string) *Standings {
+	standings := &Standings{Discipline: discipline}
+	entries := make(map[string]*StandingsEntry)
+	var order []string
+
+	for i, race := range races {
+		standings.Races = append(standings.Races, race.name)
+		for _, res := range race.results {
+			if res.StatusCode == string(models.CodeNotStarted) {
+				continue
+			}
+			key := athleteKey(res)
+			entry, ok := entries[key]
+			if !ok {
+				entry = &StandingsEntry{Athlete: fmt.Sprint(res.ID), Results: make([]RaceScore, len(races))}
+				if res.Athlete != nil && res.Athlete.Name != "" {
+					entry.Athlete = res.Athlete.Name
+					entry.Nation = res.Athlete.Nation
+				}
+				entries[key] = entry
+				order = append(order, key)
+			}
+			entry.Results[i] = RaceScore{Started: true, Rank: res.Rank, Points: champ.PointsFor(res.Rank)}
+		}
+	}
+
+	for _, key := range order {
+		entry := entries[key]
+		dropWorstResults(entry.Results, dropWorst)
+		for _, r := range entry.Results {
+			if !r.Dropped {
+				entry.Points += r.Points
+			}
+		}
+		standings.Entries = append(standings.Entries, *entry)
+	}
+
+	sort.SliceStable(standings.Entries, func(i, j int) bool {
+		a, b := standings.Entries[i], standings.Entries[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if cmp := comparePlacings(a, b); cmp != 0 {
+			return cmp > 0
+		}
+		return a.Athlete < b.Athlete
+	})
+	for i := range standings.Entries {
+		standings.Entries[i].Rank = i + 1
+		if i > 0 {
+			prev := standings.Entries[i-1]
+			if prev.Points == standings.Entries[i].Points && comparePlacings(prev, standings.Entries[i]) == 0 {
+				standings.Entries[i].Rank = prev.Rank
+			}
+		}
+	}
+	return standings
+}
+
+// Спортсмен определяется по имени и стране из реестра, иначе по стартовому номеру
+func athleteKey(res CompetitorResult) string {
+	if res.Athlete != nil && res.Athlete.Name != "" {
+		return strings.ToLower(res.Athlete.Name) + "|" + strings.ToLower(res.Athlete.Nation)
+	}
+	return fmt.Sprint("#", res.ID)
+}
+
+// dropWorstResults помечает n результатов с наименьшими очками (при равенстве - более ранние гонки)
+func dropWorstResults(results []RaceScore, n int) {
+	if n <= 0 || n >= len(results) {
+		return
+	}
+	idx := make([]int, len(results))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return results[idx[i]].Points < results[idx[j]].Points
+	})
+	for _, i := range idx[:n] {
+		results[i].Dropped = true
+	}
+}
+
+// comparePlacings сравнивает число лучших мест: >0, если у a их больше
+func comparePlacings(a, b StandingsEntry) int {
+	count := func(e StandingsEntry) map[int]int {
+		m := make(map[int]int)
+		for _, r := range e.Results {
+			if r.Rank > 0 {
+				m[r.Rank]++
+			}
+		}
+		return m
+	}
+	ca, cb := count(a), count(b)
+	worst := 0
+	for _, r := range append(append([]RaceScore{}, a.Results...), b.Results...) {
+		if r.Rank > worst {
+			worst = r.Rank
+		}
+	}
+	for rank := 1; rank <= worst; rank++ {
+		if ca[rank] != cb[rank] {
+			return ca[rank] - cb[rank]
+		}
+	}
+	return 0
+}
@@ -0,0 +1,153 @@
+package application
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+)
+
+// Палитра серий графиков
+var chartPalette = []string{
+	"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd",
+	"#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf",
+}
+
+func seriesColor(i int) string {
+	return chartPalette[i%len(chartPalette)]
+}
+
+// svgCanvas - минимальный построитель SVG-документа
+type svgCanvas struct {
+	sb     strings.Builder
+	width  int
+	height int
+}
+
+func newSVGCanvas(width, height int, title string) *svgCanvas {
+	s := &svgCanvas{width: width, height: height}
+	fmt.Fprintf(&s.sb,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&s.sb, "<title>%s</title>\n", html.EscapeString(title))
+	s.rect(0, 0, float64(width), float64(height), "#ffffff", "")
+	s.text(float64(width)/2, 22, "middle", "bold", title)
+	return s
+}
+
+func (s *svgCanvas) line(x1, y1, x2, y2 float64, stroke string, width float64) {
+	fmt.Fprintf(&s.sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%.1f"/>`+"\n",
+		x1, y1, x2, y2, stroke, width)
+}
+
+func (s *svgCanvas) rect(x, y, w, h float64, fill, tooltip string) {
+	fmt.Fprintf(&s.sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"`, x, y, w, h, fill)
+	s.closeWithTooltip("rect", tooltip)
+}
+
+func (s *svgCanvas) circle(cx, cy, r float64, fill, stroke, tooltip string) {
+	fmt.Fprintf(&s.sb, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s" stroke="%s"`, cx, cy, r, fill, stroke)
+	s.closeWithTooltip("circle", tooltip)
+}
+
+func (s *svgCanvas) polyline(points [][2]float64, stroke string) {
+	coords := make([]string, len(points))
+	for i, p := range points {
+		coords[i] = fmt.Sprintf("%.1f,%.1f", p[0], p[1])
+	}
+	fmt.Fprintf(&s.sb, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n",
+		strings.Join(coords, " "), stroke)
+}
+
+// text выводит подпись; anchor - start, middle или end, weight - пусто или bold
+func (s *svgCanvas) text(x, y float64, anchor, weight, text string) {
+	fmt.Fprintf(&s.sb, `<text x="%.1f" y="%.1f" text-anchor="%s"`, x, y, anchor)
+	if weight != "" {
+		fmt.Fprintf(&s.sb, ` font-weight="%s"`, weight)
+	}
+	fmt.Fprintf(&s.sb, ">%s</text>\n", html.EscapeString(text))
+}
+
+// Всплывающая подсказка элемента
+func (s *svgCanvas) closeWithTooltip(element, tooltip string) {
+	if tooltip == "" {
+		s.sb.WriteString("/>\n")
+		return
+	}
+	fmt.Fprintf(&s.sb, "><title>%s</title></%s>\n", html.EscapeString(tooltip), element)
+}
+
+func (s *svgCanvas) String() string {
+	return s.sb.String() + "</svg>\n"
+}
+
+// plotArea - область построения графика внутри холста
+type plotArea struct {
+	left, top, width, height float64
+}
+
+func (p plotArea) bottom() float64 { return p.top + p.height }
+func (p plotArea) right() float64  { return p.left + p.width }
+
+// niceStep подбирает "круглый" шаг делений шкалы (1, 2, 5 × 10^n) для диапазона span.
+func niceStep(span float64, ticks int) float64 {
+	if span <= 0 || ticks <= 0 {
+		return 1
+	}
+	raw := span / float64(ticks)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if raw <= m*mag {
+			return m * mag
+		}
+	}
+	return 10 * mag
+}
+
+// axisRange округляет диапазон значений до делений шкалы.
+func axisRange(lo, hi float64, ticks int) (float64, float64, float64) {
+	if hi <= lo {
+		hi = lo + 1
+	}
+	step := niceStep(hi-lo, ticks)
+	return math.Floor(lo/step) * step, math.Ceil(hi/step) * step, step
+}
+
+// yAxis рисует вертикальную шкалу с сеткой и возвращает функцию перевода значения в координату.
+func (s *svgCanvas) yAxis(area plotArea, lo, hi, step float64, label string, format func(float64) string) func(float64) float64 {
+	scale := func(v float64) float64 {
+		return area.bottom() - (v-lo)/(hi-lo)*area.height
+	}
+	for v := lo; v <= hi+step/2; v += step {
+		y := scale(v)
+		s.line(area.left, y, area.right(), y, "#e5e5e5", 1)
+		s.text(area.left-6, y+4, "end", "", format(v))
+	}
+	s.line(area.left, area.top, area.left, area.bottom(), "#333333", 1)
+	if label != "" {
+		fmt.Fprintf(&s.sb, `<text x="14" y="%.1f" text-anchor="middle" transform="rotate(-90 14 %.1f)">%s</text>`+"\n",
+			area.top+area.height/2, area.top+area.height/2, html.EscapeString(label))
+	}
+	return scale
+}
+
+// legend выводит подписи серий справа от области построения
+func (s *svgCanvas) legend(area plotArea, labels []string) {
+	x := area.right() + 16
+	for i, label := range labels {
+		y := area.top + float64(i)*18
+		s.rect(x, y, 12, 12, seriesColor(i), "")
+		s.text(x+18, y+10, "start", "", label)
+	}
+}
+
+// formatSeconds - подпись шкалы времени в формате M:SS
+func formatSeconds(v float64) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	total := int(math.Round(v))
+	return fmt.Sprintf("%s%d:%02d", sign, total/60, total%60)
+}
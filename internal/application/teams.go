@@ -0,0 +1,92 @@
+package application
+
+import (
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"sort"
+	"time"
+)
+
+// TeamResult - строка командного зачёта
+type TeamResult struct {
+	Rank      int // Место; 0 - команда не набрала нужного числа спортсменов с результатом
+	Team      string
+	Time      time.Duration // Сумма времён при подсчёте по времени
+	Points    int           // Сумма очков при подсчёте по очкам
+	Finishers int           // Спортсменов команды с результатом
+	Members   []ResultRow   // Спортсмены, идущие в зачёт, в порядке протокола
+}
+
+// ClassifyTeams строит командный зачёт по протоколу гонки. Спортсмены без значения поля
+// команды (страны или клуба) в зачёт не входят.
+func ClassifyTeams(rows []ResultRow, scoring *models.TeamScoring) []TeamResult {
+	teams := make(map[string]*TeamResult)
+	var order []string
+	for _, row := range rows {
+		c := row.Competitor
+		if c.Athlete == nil {
+			continue
+		}
+		name, _ := c.Athlete.Field(scoring.By)
+		if name == "" {
+			continue
+		}
+		team, ok := teams[name]
+		if !ok {
+			team = &TeamResult{Team: name}
+			teams[name] = team
+			order = append(order, name)
+		}
+		if !countsForTeam(row, scoring) {
+			continue
+		}
+		team.Finishers++
+		if len(team.Members) < scoring.Best {
+			team.Members = append(team.Members, row)
+			team.Time += c.TotalTime()
+			team.Points += scoring.PointsFor(row.Rank)
+		}
+	}
+
+	results := make([]TeamResult, 0, len(order))
+	for _, name := range order {
+		results = append(results, *teams[name])
+	}
+	classified := func(t TeamResult) bool {
+		return t.Finishers >= scoring.MinFinishers
+	}
+	better := func(a, b TeamResult) bool {
+		if scoring.Scoring == models.TeamScoringPoints {
+			return a.Points > b.Points
+		}
+		return a.Time < b.Time
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if ca, cb := classified(a), classified(b); ca != cb {
+			return ca
+		}
+		if classified(a) && (better(a, b) || better(b, a)) {
+			return better(a, b)
+		}
+		return a.Team < b.Team
+	})
+
+	for i := range results {
+		if !classified(results[i]) {
+			continue
+		}
+		results[i].Rank = i + 1
+		if i > 0 && !better(results[i-1], results[i]) {
+			results[i].Rank = results[i-1].Rank
+		}
+	}
+	return results
+}
+
+// countsForTeam: по времени считаются только финишировавшие, по очкам - все, получившие место
+func countsForTeam(row ResultRow, scoring *models.TeamScoring) bool {
+	if scoring.Scoring == models.TeamScoringPoints {
+		return row.Rank > 0
+	}
+	return row.Competitor.Status == models.Finished
+}
@@ -0,0 +1,111 @@
+package application
+
+import (
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"github.com/BiathlonRaceProto-Yadro/pkg/utils"
+	"strings"
+	"time"
+)
+
+// describeEvent - описание входящего события для хронологии участника
+func (p *EventProcessor) describeEvent(e models.Event) string {
+	name, ok := p.catalog.Lookup(fmt.Sprintf("event.%d", int(e.Type)))
+	if !ok {
+		name = e.Type.String()
+	}
+	text := fmt.Sprintf("(%d) %s", int(e.Type), name)
+	if len(e.ExtraParams) > 0 {
+		text += ": " + strings.Join(e.ExtraParams, " ")
+	}
+	return text
+}
+
+// progress - состояние участника до обработки события, чтобы выделить вычисленные события
+type progress struct {
+	status    models.CompetitorStatus
+	laps      int
+	sessions  int
+	penalties int
+	splits    int
+}
+
+func progressOf(c *models.Competitor) progress {
+	p := progress{status: c.RaceStatus(), laps: c.CompletedLaps(), splits: len(c.Splits)}
+	for _, s := range c.FiringLines {
+		if s.Finished() {
+			p.sessions++
+		}
+	}
+	for _, lap := range c.PenaltyLaps() {
+		if !lap.Finish.IsZero() {
+			p.penalties++
+		}
+	}
+	return p
+}
+
+// noteDerived добавляет в хронологию круги, рубежи, штрафные круги, отсечки и финиш,
+// завершённые при обработке события.
+func (p *EventProcessor) noteDerived(c *models.Competitor, before progress, t time.Time) {
+	course := p.course(c)
+
+	for _, st := range c.Splits[before.splits:] {
+		c.Note(t, models.TimelineDerived, p.catalog.T("timeline.split",
+			st.SplitID, st.Lap, utils.FormatDuration(c.Elapsed(st.Time))))
+	}
+
+	stage := 0
+	for i, s := range c.FiringLines {
+		if !s.Finished() {
+			continue
+		}
+		stage++
+		if stage <= before.sessions {
+			continue
+		}
+		position := ""
+		if s.Position() != "" {
+			position = fmt.Sprintf(" (%s)", positionLabel(p.catalog, s.Position().String()))
+		}
+		c.Note(t, models.TimelineDerived, p.catalog.T("timeline.stage",
+			i+1, s.Line(), position, s.Hits(), s.Targets(), s.Rounds(), s.Missed(), utils.FormatDuration(s.RangeTime())))
+	}
+
+	missed := c.PenaltyMissedShots()
+	completed := 0
+	for i, lap := range c.PenaltyLaps() {
+		if lap.Finish.IsZero() {
+			continue
+		}
+		completed++
+		if completed <= before.penalties {
+			continue
+		}
+		distance := 0
+		if i < len(missed) {
+			distance = missed[i] * course.PenaltyLen
+		}
+		d := lap.Finish.Sub(lap.Start)
+		c.Note(t, models.TimelineDerived, p.catalog.T("timeline.penalty",
+			i+1, distance, utils.FormatDuration(d), formatSpeedValue(speed(distance, d))))
+	}
+
+	completed = 0
+	for _, lap := range c.MainLaps() {
+		if lap.Finish.IsZero() {
+			continue
+		}
+		completed++
+		if completed <= before.laps {
+			continue
+		}
+		d := lap.Finish.Sub(lap.Start)
+		c.Note(t, models.TimelineDerived, p.catalog.T("timeline.lap",
+			completed, utils.FormatDuration(d), speed(course.LapLen, d)))
+	}
+
+	if c.RaceStatus() == models.Finished && before.status != models.Finished {
+		c.Note(t, models.TimelineDerived, p.catalog.T("timeline.finished", utils.FormatDuration(c.TotalTime())))
+	}
+}
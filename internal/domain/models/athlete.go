@@ -0,0 +1,72 @@
+package models
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Athlete - запись реестра спортсменов. Номер (Bib) совпадает с ID участника в событиях.
+type Athlete struct {
+	Bib       int
+	Name      string
+	Nation    string
+	Club      string
+	BirthYear int
+	Category  string
+}
+
+// Registry - реестр спортсменов по стартовым номерам
+type Registry struct {
+	athletes map[int]*Athlete
+}
+
+func NewRegistry(athletes []Athlete) *Registry {
+	r := &Registry{athletes: make(map[int]*Athlete, len(athletes))}
+	for i := range athletes {
+		a := athletes[i]
+		r.athletes[a.Bib] = &a
+	}
+	return r
+}
+
+func (r *Registry) Lookup(bib int) (*Athlete, bool) {
+	if r == nil {
+		return nil, false
+	}
+	a, ok := r.athletes[bib]
+	return a, ok
+}
+
+// Bibs возвращает номера всех спортсменов реестра по возрастанию.
+func (r *Registry) Bibs() []int {
+	if r == nil {
+		return nil
+	}
+	bibs := make([]int, 0, len(r.athletes))
+	for bib := range r.athletes {
+		bibs = append(bibs, bib)
+	}
+	sort.Ints(bibs)
+	return bibs
+}
+
+// Field возвращает значение поля спортсмена по имени (для фильтров и шаблонов).
+func (a *Athlete) Field(name string) (string, bool) {
+	switch strings.ToLower(name) {
+	case "bib":
+		return strconv.Itoa(a.Bib), true
+	case "name":
+		return a.Name, true
+	case "nation":
+		return a.Nation, true
+	case "club":
+		return a.Club, true
+	case "birthyear":
+		return strconv.Itoa(a.BirthYear), true
+	case "category":
+		return a.Category, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+// Championship - манифест соревновательного дня: набор гонок с их файлами
+type Championship struct {
+	Name      string
+	Races     []RaceEntry
+	Points    []int // Очки за места в общем зачёте: Points[0] - за первое место
+	DropWorst int   // Число худших результатов, не учитываемых в общем зачёте
+}
+
+// DefaultPointsTable - очки за места 1-40 по образцу Кубка мира
+var DefaultPointsTable = []int{
+	90, 75, 60, 50, 45, 40, 36, 34, 32, 31,
+	30, 29, 28, 27, 26, 25, 24, 23, 22, 21,
+	20, 19, 18, 17, 16, 15, 14, 13, 12, 11,
+	10, 9, 8, 7, 6, 5, 4, 3, 2, 1,
+}
+
+// PointsFor возвращает очки за место; места вне таблицы очков не приносят
+func (c *Championship) PointsFor(rank int) int {
+	if rank < 1 || rank > len(c.Points) {
+		return 0
+	}
+	return c.Points[rank-1]
+}
+
+// RaceEntry - одна гонка чемпионата
+type RaceEntry struct {
+	Name     string
+	Config   string // Путь к конфигурации гонки
+	Events   string // Путь к файлу событий
+	Results  string // Путь к сохранённому результату (XML, JSON) вместо конфигурации и событий
+	Athletes string // Необязательный путь к реестру спортсменов
+	Format   string // Формат гонки (для сводки и зачёта по дисциплинам)
+	Category string // Категория (для сводки)
+}
@@ -1,7 +1,6 @@
 package models
 
 import (
-	"fmt"
 	"log/slog"
 	"time"
 )
@@ -18,10 +17,7 @@ const (
 	Disqualified
 	NotStarted
 	NotFinished
-)
-
-const (
-	maxShots = 5
+	Lapped
 )
 
 type Lap struct {
@@ -33,6 +29,8 @@ type Lap struct {
 
 type Competitor struct {
 	ID                     int
+	Athlete                *Athlete // Данные из реестра спортсменов, если он загружен
+	Category               string   // Категория участника; пусто, если категории не заданы
 	Status                 CompetitorStatus
 	Scheduled              time.Time
 	ActualStart            time.Time
@@ -41,48 +39,145 @@ type Competitor struct {
 	Hits, Shots            int
 	DisqualificationReason string
 	FiringLines            []firingSession
+	Splits                 []SplitTime
+	Checkpoints            []Checkpoint
+	LappedAt               time.Time     // Момент, когда участника обогнали на круг
+	TimeAdjustment         time.Duration // Штрафное время, назначенное жюри
+	JuryActions            []JuryAction
+	Timeline               []TimelineEntry  // Хронология входящих и вычисленных событий участника
+	statusBeforeDSQ        CompetitorStatus // Статус в гонке под дисквалификацией (см. UpdateStatus)
 	logger                 *slog.Logger
 }
 
+type CheckpointKind int
+
+const (
+	RangeExit CheckpointKind = iota + 1 // Выход со стрелкового рубежа
+	LapEnd                              // Окончание основного круга
+)
+
+// Checkpoint - контрольная точка, в которой сравниваются участники
+type Checkpoint struct {
+	Kind  CheckpointKind
+	Index int // Порядковый номер точки данного вида у участника (с 1)
+	Time  time.Time
+}
+
+func (k CheckpointKind) String() string {
+	switch k {
+	case RangeExit:
+		return "Range"
+	case LapEnd:
+		return "Lap"
+	default:
+		return "Unknown"
+	}
+}
+
+// SplitTime - прохождение отсечки промежуточного времени
+type SplitTime struct {
+	Lap     int       // Номер основного круга
+	SplitID string    // Идентификатор отсечки
+	Time    time.Time // Время прохождения
+}
+
 type firingSession struct {
 	line      int
+	position  ShootingPosition
 	entryTime time.Time
 	endTime   time.Time
 	hits      map[int]bool
-	maxShots  int
+	targets   int         // Количество мишеней на рубеже
+	spares    int         // Доступные дозарядные патроны
+	rounds    int         // Фактически произведённые выстрелы
+	shotTimes []time.Time // Время выстрелов (событие 12)
+	hitTimes  []time.Time // Время поражения мишеней (событие 6)
 }
 
 func NewCompetitor(id int, logger *slog.Logger) *Competitor {
 	return &Competitor{ID: id, Status: Registered, logger: logger}
 }
 
+// Допустимые переходы статусов. Дисквалификация возможна из любого состояния,
+// в том числе после финиша. Из Disqualified переходов нет: снять дисквалификацию
+// может только решение жюри (см. ApplyJuryAction).
 var transitions = map[CompetitorStatus][]CompetitorStatus{
-	Registered:    {OnStart, NotStarted},
-	OnStart:       {Racing, NotStarted},
-	Racing:        {InFiringRange, InPenalty, Finished, NotFinished},
-	InFiringRange: {Racing, InPenalty, NotFinished},
-	InPenalty:     {Racing, NotFinished},
+	Registered:    {OnStart, NotStarted, Disqualified},
+	OnStart:       {Racing, NotStarted, Disqualified},
+	Racing:        {InFiringRange, InPenalty, Finished, NotFinished, Lapped, Disqualified},
+	InFiringRange: {Racing, InPenalty, NotFinished, Lapped, Disqualified},
+	InPenalty:     {Racing, NotFinished, Lapped, Disqualified},
+	Finished:      {Disqualified},
+	NotFinished:   {Disqualified},
+	NotStarted:    {Disqualified},
+	Lapped:        {Disqualified},
+	Disqualified:  {},
 }
 
+// UpdateStatus переводит участника в статус next. Дисквалификация накладывается поверх
+// статуса в гонке: пока она действует, события продолжают менять статус в гонке,
+// и после восстановления решением жюри участник получает свой фактический результат.
 func (c *Competitor) UpdateStatus(next CompetitorStatus) error {
-	if next == Disqualified {
-		c.Status = next
-		return nil
+	current := c.RaceStatus()
+	allowed := transitions[current]
+	if c.Status == Disqualified && next == Disqualified {
+		allowed = nil
 	}
-
-	allowed := transitions[c.Status]
 	for _, s := range allowed {
 		if s == next {
-			c.Status = next
+			c.noteStatus(current, next)
+			switch {
+			case next == Disqualified:
+				c.statusBeforeDSQ = c.Status
+				c.Status = Disqualified
+			case c.Status == Disqualified:
+				c.statusBeforeDSQ = next
+			default:
+				c.Status = next
+			}
 			return nil
 		}
 	}
 
-	err := fmt.Errorf("invalid transition %v -> %v", c.Status, next)
+	err := newRuleError("invalidTransition", "invalid transition %v -> %v", current, next)
 	c.logger.Error("Status transition error", "error", err)
 	return err
 }
 
+// RaceStatus - статус участника в гонке без учёта дисквалификации
+func (c *Competitor) RaceStatus() CompetitorStatus {
+	if c.Status == Disqualified {
+		return c.statusBeforeDSQ
+	}
+	return c.Status
+}
+
+// ApplyJuryAction применяет решение жюри к участнику и сохраняет его в истории.
+func (c *Competitor) ApplyJuryAction(action JuryAction) error {
+	switch action.Type {
+	case JuryDisqualify:
+		c.DisqualificationReason = action.Reason
+		if err := c.UpdateStatus(Disqualified); err != nil {
+			return err
+		}
+	case JuryTimePenalty:
+		c.TimeAdjustment += action.Penalty
+	case JuryReinstate:
+		if c.Status != Disqualified {
+			err := newRuleError("notDisqualified", "competitor %d is not disqualified", c.ID)
+			c.logger.Error("Reinstatement error", "error", err)
+			return err
+		}
+		c.noteStatus(c.Status, c.statusBeforeDSQ)
+		c.Status = c.statusBeforeDSQ
+		c.DisqualificationReason = ""
+	default:
+		return newRuleError("juryActionNotForCompetitor", "jury action %v does not apply to a competitor", action.Type)
+	}
+	c.JuryActions = append(c.JuryActions, action)
+	return nil
+}
+
 func (c *Competitor) SetScheduled(t time.Time) {
 	c.Scheduled = t
 }
@@ -94,7 +189,7 @@ func (c *Competitor) StartNewLap(penal bool, t time.Time) {
 
 func (c *Competitor) FinishCurrentLap(t time.Time) error {
 	if len(c.Laps) == 0 {
-		err := fmt.Errorf("no lap in progress")
+		err := newRuleError("noLapInProgress", "no lap in progress")
 		c.logger.Error("Lap completion error", "error", err, "competitorID", c.ID)
 		return err
 	}
@@ -107,7 +202,7 @@ func (c *Competitor) FinishCurrentLap(t time.Time) error {
 			return nil
 		}
 	}
-	err := fmt.Errorf("no unfinished main lap found")
+	err := newRuleError("noUnfinishedLap", "no unfinished main lap found")
 	c.logger.Error("Failed to finish lap", "error", err, "competitorID", c.ID, "currentLaps", c.Laps)
 	return err
 }
@@ -135,40 +230,140 @@ func (c *Competitor) SetFinish(t time.Time) {
 	c.FinishTime = t
 }
 
-func (c *Competitor) StartFiring(line, spots int, t time.Time) {
+func (c *Competitor) StartFiring(line, targets, spares int, position ShootingPosition, t time.Time) {
 	s := firingSession{
 		line:      line,
+		position:  position,
 		entryTime: t,
 		hits:      make(map[int]bool),
-		maxShots:  maxShots,
+		targets:   targets,
+		spares:    spares,
 	}
 	c.FiringLines = append(c.FiringLines, s)
-	c.Shots += maxShots // Учитываем все выстрелы за гонку
 }
 
-func (c *Competitor) RegisterShot(target int) {
+func (c *Competitor) RegisterShot(target int, t time.Time) {
 	if len(c.FiringLines) == 0 {
 		return
 	}
 	s := &c.FiringLines[len(c.FiringLines)-1]
 	if !s.hits[target] {
 		s.hits[target] = true
+		s.hitTimes = append(s.hitTimes, t)
 		c.Hits++
 	}
 }
 
+// RegisterRound учитывает очередной выстрел на текущем рубеже.
+// Выстрелы сверх количества мишеней расходуют дозарядные патроны.
+func (c *Competitor) RegisterRound(t time.Time) error {
+	if len(c.FiringLines) == 0 {
+		err := newRuleError("noFiringSession", "no firing session in progress")
+		c.logger.Error("Shot registration error", "error", err, "competitorID", c.ID)
+		return err
+	}
+	s := &c.FiringLines[len(c.FiringLines)-1]
+	if !s.endTime.IsZero() {
+		err := newRuleError("firingFinished", "firing session on line %d already finished", s.line)
+		c.logger.Error("Shot registration error", "error", err, "competitorID", c.ID)
+		return err
+	}
+	if s.rounds >= s.targets+s.spares {
+		err := newRuleError("noRoundsLeft", "no rounds left: %d fired, %d targets, %d spares", s.rounds, s.targets, s.spares)
+		c.logger.Error("Shot registration error", "error", err, "competitorID", c.ID)
+		return err
+	}
+	s.rounds++
+	s.shotTimes = append(s.shotTimes, t)
+	c.Shots++
+	return nil
+}
+
+// FinishFiring закрывает текущий рубеж и возвращает число непоражённых мишеней.
+// Если выстрелы не приходили отдельными событиями, считаем, что по каждой мишени
+// был произведён ровно один выстрел. Каждое попадание - хотя бы один выстрел,
+// поэтому выстрелов на рубеже не меньше, чем поражённых мишеней.
 func (c *Competitor) FinishFiring(t time.Time) int {
 	s := &c.FiringLines[len(c.FiringLines)-1]
 	s.endTime = t
-	missed := maxShots - len(s.hits)
-	return missed
+	rounds := s.rounds
+	if rounds == 0 {
+		rounds = s.targets
+	}
+	rounds = max(rounds, len(s.hits))
+	c.Shots += rounds - s.rounds
+	s.rounds = rounds
+	return s.missed()
+}
+
+// RangeTime - суммарное время на всех завершённых рубежах.
+func (c *Competitor) RangeTime() time.Duration {
+	var total time.Duration
+	for _, s := range c.FiringLines {
+		total += s.RangeTime()
+	}
+	return total
+}
+
+// ShootingTime - суммарное время стрельбы (от первого до последнего выстрела) на всех рубежах.
+func (c *Competitor) ShootingTime() time.Duration {
+	var total time.Duration
+	for _, s := range c.FiringLines {
+		total += s.ShootingTime()
+	}
+	return total
+}
+
+// Accuracy - процент попаданий от фактически произведённых выстрелов.
+func (c *Competitor) Accuracy() float64 {
+	if c.Shots == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(c.Shots) * 100
+}
+
+// RecordSplit фиксирует прохождение отсечки на текущем основном круге.
+func (c *Competitor) RecordSplit(id string, t time.Time) error {
+	mainLaps := c.MainLaps()
+	lap := len(mainLaps)
+	if lap == 0 || !mainLaps[lap-1].Finish.IsZero() {
+		err := newRuleError("splitNoLap", "split %q passed with no lap in progress", id)
+		c.logger.Error("Split registration error", "error", err, "competitorID", c.ID)
+		return err
+	}
+	for _, sp := range c.Splits {
+		if sp.Lap == lap && sp.SplitID == id {
+			err := newRuleError("splitPassed", "split %q already passed on lap %d", id, lap)
+			c.logger.Error("Split registration error", "error", err, "competitorID", c.ID)
+			return err
+		}
+	}
+	c.Splits = append(c.Splits, SplitTime{Lap: lap, SplitID: id, Time: t})
+	return nil
+}
+
+// PassCheckpoint фиксирует прохождение контрольной точки (выход с рубежа или конец круга).
+func (c *Competitor) PassCheckpoint(kind CheckpointKind, t time.Time) {
+	index := 1
+	for _, cp := range c.Checkpoints {
+		if cp.Kind == kind {
+			index++
+		}
+	}
+	c.Checkpoints = append(c.Checkpoints, Checkpoint{Kind: kind, Index: index, Time: t})
 }
 
+// Elapsed - время гонки участника на момент t (от запланированного старта).
+func (c *Competitor) Elapsed(t time.Time) time.Duration {
+	return t.Sub(c.Scheduled)
+}
+
+// TotalTime - итоговое время с учётом штрафного времени от жюри.
 func (c *Competitor) TotalTime() time.Duration {
 	if c.FinishTime.IsZero() {
 		return 0
 	}
-	return c.FinishTime.Sub(c.Scheduled)
+	return c.FinishTime.Sub(c.Scheduled) + c.TimeAdjustment
 }
 
 func (c *Competitor) AverageSpeed(distance int, laps []Lap) float64 {
@@ -182,6 +377,67 @@ func (c *Competitor) AverageSpeed(distance int, laps []Lap) float64 {
 	return float64(distance*len(laps)) / total.Seconds()
 }
 
+// NetLapDuration - чистое время основного круга без штрафных кругов
+// и, при excludeRange, без времени на стрелковом рубеже.
+func (c *Competitor) NetLapDuration(lap Lap, excludeRange bool) time.Duration {
+	if lap.Finish.IsZero() {
+		return 0
+	}
+	within := func(start, finish time.Time) bool {
+		return !finish.IsZero() && !start.Before(lap.Start) && !finish.After(lap.Finish)
+	}
+
+	net := lap.Finish.Sub(lap.Start)
+	for _, penalty := range c.PenaltyLaps() {
+		if within(penalty.Start, penalty.Finish) {
+			net -= penalty.Finish.Sub(penalty.Start)
+		}
+	}
+	if excludeRange {
+		for _, s := range c.FiringLines {
+			if within(s.entryTime, s.endTime) {
+				net -= s.RangeTime()
+			}
+		}
+	}
+	if net < 0 {
+		net = 0
+	}
+	return net
+}
+
+// CompletedLaps - количество завершённых основных кругов.
+func (c *Competitor) CompletedLaps() int {
+	count := 0
+	for _, lap := range c.MainLaps() {
+		if !lap.Finish.IsZero() {
+			count++
+		}
+	}
+	return count
+}
+
+// LastLapFinish - время завершения последнего основного круга.
+func (c *Competitor) LastLapFinish() time.Time {
+	var last time.Time
+	for _, lap := range c.MainLaps() {
+		if !lap.Finish.IsZero() {
+			last = lap.Finish
+		}
+	}
+	return last
+}
+
+// OnCourse - участник стартовал и ещё находится на трассе.
+func (c *Competitor) OnCourse() bool {
+	switch c.Status {
+	case Racing, InFiringRange, InPenalty:
+		return true
+	default:
+		return false
+	}
+}
+
 func (c *Competitor) MainLaps() []Lap {
 	var mainLaps []Lap
 	for _, lap := range c.Laps {
@@ -202,13 +458,82 @@ func (c *Competitor) PenaltyLaps() []Lap {
 	return penaltyLaps
 }
 
+// PenaltyMissedShots возвращает число штрафных кругов по каждому рубежу,
+// после которого участник ушёл на штрафной круг (индексы совпадают с PenaltyLaps).
 func (c *Competitor) PenaltyMissedShots() []int {
 	var missed []int
 	for _, session := range c.FiringLines {
-		missedShots := session.maxShots - len(session.hits)
-		if missedShots >= 0 {
+		missedShots := session.missed()
+		if missedShots > 0 {
 			missed = append(missed, missedShots)
 		}
 	}
 	return missed
 }
+
+// Количество мишеней, оставшихся стоять после всех выстрелов (включая дозарядные)
+func (s *firingSession) missed() int {
+	return s.targets - len(s.hits)
+}
+
+// Методы доступа к данным рубежа для отчётов.
+func (s firingSession) Line() int                  { return s.line }
+func (s firingSession) Position() ShootingPosition { return s.position }
+func (s firingSession) Targets() int               { return s.targets }
+func (s firingSession) Rounds() int                { return s.rounds }
+func (s firingSession) Hits() int                  { return len(s.hits) }
+func (s firingSession) Missed() int                { return s.missed() }
+func (s firingSession) EntryTime() time.Time       { return s.entryTime }
+func (s firingSession) EndTime() time.Time         { return s.endTime }
+func (s firingSession) Finished() bool             { return !s.endTime.IsZero() }
+
+// Hit сообщает, поражена ли мишень с номером target (с 1).
+func (s firingSession) Hit(target int) bool { return s.hits[target] }
+
+// RangeTime - время от входа на рубеж до выхода с него.
+func (s firingSession) RangeTime() time.Duration {
+	if s.endTime.IsZero() {
+		return 0
+	}
+	return s.endTime.Sub(s.entryTime)
+}
+
+// ShotTimes возвращает время выстрелов. Если отдельных событий выстрела не было,
+// используется время поражения мишеней.
+func (s firingSession) ShotTimes() []time.Time {
+	if len(s.shotTimes) > 0 {
+		return s.shotTimes
+	}
+	return s.hitTimes
+}
+
+// TimeToFirstShot - время от входа на рубеж до первого выстрела.
+func (s firingSession) TimeToFirstShot() time.Duration {
+	shots := s.ShotTimes()
+	if len(shots) == 0 {
+		return 0
+	}
+	return shots[0].Sub(s.entryTime)
+}
+
+// ShootingTime - время от первого до последнего выстрела.
+func (s firingSession) ShootingTime() time.Duration {
+	shots := s.ShotTimes()
+	if len(shots) < 2 {
+		return 0
+	}
+	return shots[len(shots)-1].Sub(shots[0])
+}
+
+// Rhythm - интервалы между последовательными выстрелами.
+func (s firingSession) Rhythm() []time.Duration {
+	shots := s.ShotTimes()
+	if len(shots) < 2 {
+		return nil
+	}
+	intervals := make([]time.Duration, 0, len(shots)-1)
+	for i := 1; i < len(shots); i++ {
+		intervals = append(intervals, shots[i].Sub(shots[i-1]))
+	}
+	return intervals
+}
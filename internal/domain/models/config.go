@@ -11,6 +11,42 @@ type Config struct {
 	FiringLines int           // Количество стрелковых рубежей на круг
 	Start       time.Time     // Планируемое время старта первого участника
 	StartDelta  time.Duration // Планируемый интервал между стартами
+
+	ShotsPerStage int // Количество мишеней (основных выстрелов) на одном рубеже
+	SpareRounds   int // Количество дозарядных патронов на одном рубеже
+
+	Format           string             // Формат гонки (sprint, individual, pursuit, ...)
+	ShootingSequence []ShootingPosition // Порядок стрельбы по рубежам (P-S-P-S)
+
+	Splits []SplitPoint // Отсечки промежуточного времени на круге
+
+	StatusLabels map[StatusCode]string // Подписи статусов в отчётах
+
+	LappedRule string // Правило для обогнанных на круг (pull, flag); пусто - не проверять
+
+	Categories []Category // Категории со своими параметрами трассы и стартовым окном
+
+	Teams *TeamScoring // Командный зачёт; nil - не строится
+}
+
+// Category - категория участников (юниоры, взрослые, ветераны) со своей дистанцией
+type Category struct {
+	Name    string
+	BibFrom int // Диапазон номеров категории; 0 - назначение только через реестр
+	BibTo   int
+	Course  *Config // Параметры трассы и старта категории
+}
+
+// Правила для обогнанных на круг участников
+const (
+	LappedPull = "pull" // Снять с трассы со статусом LAP
+	LappedFlag = "flag" // Только отметить
+)
+
+// SplitPoint - отсечка промежуточного времени на трассе
+type SplitPoint struct {
+	ID       string // Имя или номер отсечки
+	Distance int    // Расстояние от начала круга, м
 }
 
 func NewConfig(
@@ -20,6 +56,8 @@ func NewConfig(
 	firingLines int,
 	start time.Time,
 	startDelta time.Duration,
+	shotsPerStage int,
+	spareRounds int,
 ) *Config {
 	return &Config{
 		Laps:        laps,
@@ -28,5 +66,55 @@ func NewConfig(
 		FiringLines: firingLines,
 		Start:       start,
 		StartDelta:  startDelta,
+
+		ShotsPerStage: shotsPerStage,
+		SpareRounds:   spareRounds,
+	}
+}
+
+// PositionForStage возвращает положение для стрельбы на рубеже с порядковым номером stage (с 1).
+func (c *Config) PositionForStage(stage int) ShootingPosition {
+	if len(c.ShootingSequence) == 0 || stage < 1 {
+		return ""
+	}
+	return c.ShootingSequence[(stage-1)%len(c.ShootingSequence)]
+}
+
+// FindSplit ищет отсечку по идентификатору.
+func (c *Config) FindSplit(id string) (SplitPoint, bool) {
+	for _, sp := range c.Splits {
+		if sp.ID == id {
+			return sp, true
+		}
+	}
+	return SplitPoint{}, false
+}
+
+// FindCategory ищет категорию по имени.
+func (c *Config) FindCategory(name string) (*Category, bool) {
+	for i := range c.Categories {
+		if c.Categories[i].Name == name {
+			return &c.Categories[i], true
+		}
+	}
+	return nil, false
+}
+
+// CategoryForBib возвращает категорию, в диапазон номеров которой попадает bib.
+func (c *Config) CategoryForBib(bib int) (*Category, bool) {
+	for i := range c.Categories {
+		cat := &c.Categories[i]
+		if cat.BibFrom > 0 && bib >= cat.BibFrom && bib <= cat.BibTo {
+			return cat, true
+		}
+	}
+	return nil, false
+}
+
+// CourseFor возвращает параметры трассы для категории; без категории - общие.
+func (c *Config) CourseFor(category string) *Config {
+	if cat, ok := c.FindCategory(category); ok {
+		return cat.Course
 	}
+	return c
 }
@@ -0,0 +1,19 @@
+package models
+
+import "fmt"
+
+// RuleError - событие нарушает правила гонки. Key и Args позволяют вывести сообщение
+// на языке отчёта (ключ каталога error.<Key>); Error() возвращает текст на английском.
+type RuleError struct {
+	Key  string
+	Args []any
+	text string
+}
+
+func newRuleError(key, format string, args ...any) *RuleError {
+	return &RuleError{Key: key, Args: args, text: fmt.Sprintf(format, args...)}
+}
+
+func (e *RuleError) Error() string {
+	return e.text
+}
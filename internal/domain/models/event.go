@@ -20,8 +20,17 @@ const (
 	LeftPenalty                               // Участник завершил штрафные круги
 	LapFinished                               // Участник завершил основной круг
 	CannotContinue                            // Участник не может продолжить
+	ShotFired                                 // Участник произвёл выстрел (включая дозарядные патроны)
+	PassedSplit                               // Участник прошёл отсечку промежуточного времени
+	JuryDisqualified                          // Жюри дисквалифицировало участника
+	JuryTimeAdjusted                          // Жюри добавило или сняло штрафное время
+	JuryReinstated                            // Жюри восстановило участника после протеста
+	JuryResultsState                          // Жюри объявило протокол предварительным или официальным
 )
 
+// lastEventType - последний известный тип входящего события
+const lastEventType = JuryResultsState
+
 const timeLayout = "15:04:05.000"
 
 type Event struct {
@@ -46,7 +55,7 @@ func NewEvent(
 }
 
 func ParseEventType(code int) (EventType, error) {
-	if code < 1 || code > 11 {
+	if code < 1 || code > int(lastEventType) {
 		return 0, fmt.Errorf("invalid events type code")
 	}
 	return EventType(code), nil
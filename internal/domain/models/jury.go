@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+)
+
+type JuryActionType int
+
+const (
+	JuryDisqualify  JuryActionType = iota + 1 // Дисквалификация
+	JuryTimePenalty                           // Добавление или снятие штрафного времени
+	JuryReinstate                             // Восстановление после протеста
+	JuryResultState                           // Изменение статуса протокола
+)
+
+// Статусы протокола
+const (
+	ResultsProvisional = "provisional"
+	ResultsOfficial    = "official"
+)
+
+// JuryAction - решение жюри
+type JuryAction struct {
+	Time         time.Time
+	Type         JuryActionType
+	CompetitorID int           // 0 для решений по всей гонке
+	Rule         string        // Ссылка на пункт правил
+	Penalty      time.Duration // Штрафное время (отрицательное - снятие)
+	State        string        // Статус протокола для JuryResultState
+	Reason       string
+}
+
+// Jury - журнал решений жюри по гонке
+type Jury struct {
+	State   string // Статус протокола; пустой, если жюри его не объявляло
+	Actions []JuryAction
+}
+
+func NewJury() *Jury {
+	return &Jury{}
+}
+
+func (j *Jury) Record(action JuryAction) {
+	j.Actions = append(j.Actions, action)
+	if action.Type == JuryResultState {
+		j.State = action.State
+	}
+}
+
+func (t JuryActionType) String() string {
+	switch t {
+	case JuryDisqualify:
+		return "Disqualified"
+	case JuryTimePenalty:
+		return "Time penalty"
+	case JuryReinstate:
+		return "Reinstated"
+	case JuryResultState:
+		return "Results"
+	default:
+		return "Unknown"
+	}
+}
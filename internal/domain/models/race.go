@@ -0,0 +1,10 @@
+package models
+
+// Race - результат обработки одной гонки, передаваемый в отчёты
+type Race struct {
+	Config      *Config
+	Competitors []*Competitor
+	Jury        *Jury
+	Registry    *Registry // nil, если реестр спортсменов не загружен
+	Warnings    []string  // Замечания проверки данных
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ShootingPosition string
+
+const (
+	Prone    ShootingPosition = "P" // Стрельба лёжа
+	Standing ShootingPosition = "S" // Стрельба стоя
+)
+
+// Форматы гонок
+const (
+	FormatSprint     = "sprint"
+	FormatIndividual = "individual"
+	FormatPursuit    = "pursuit"
+	FormatMassStart  = "massStart"
+	FormatRelay      = "relay"
+)
+
+// Стандартный порядок стрельбы для каждого формата
+var defaultSequences = map[string][]ShootingPosition{
+	FormatSprint:     {Prone, Standing},
+	FormatIndividual: {Prone, Standing, Prone, Standing},
+	FormatPursuit:    {Prone, Prone, Standing, Standing},
+	FormatMassStart:  {Prone, Prone, Standing, Standing},
+	FormatRelay:      {Prone, Standing},
+}
+
+// DefaultShootingSequence возвращает порядок стрельбы для формата гонки.
+func DefaultShootingSequence(format string) ([]ShootingPosition, error) {
+	seq, ok := defaultSequences[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown race format %q", format)
+	}
+	return seq, nil
+}
+
+// ParseShootingSequence разбирает строку вида "P-S-P-S".
+func ParseShootingSequence(raw string) ([]ShootingPosition, error) {
+	var seq []ShootingPosition
+	for _, part := range strings.Split(raw, "-") {
+		switch pos := ShootingPosition(strings.ToUpper(strings.TrimSpace(part))); pos {
+		case Prone, Standing:
+			seq = append(seq, pos)
+		default:
+			return nil, fmt.Errorf("invalid shooting position %q", part)
+		}
+	}
+	return seq, nil
+}
+
+func (p ShootingPosition) String() string {
+	switch p {
+	case Prone:
+		return "Prone"
+	case Standing:
+		return "Standing"
+	default:
+		return "Unknown"
+	}
+}
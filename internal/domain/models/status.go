@@ -0,0 +1,36 @@
+package models
+
+// StatusCode - код результата участника в итоговом протоколе
+type StatusCode string
+
+const (
+	CodeFinished     StatusCode = "FIN"  // Финишировал
+	CodeNotStarted   StatusCode = "DNS"  // Не стартовал
+	CodeNotFinished  StatusCode = "DNF"  // Не финишировал
+	CodeDisqualified StatusCode = "DSQ"  // Дисквалифицирован
+	CodeLapped       StatusCode = "LAP"  // Обогнан на круг и снят с трассы
+	CodeProvisional  StatusCode = "PROV" // Ещё на трассе, результат предварительный
+)
+
+// StatusCodes возвращает все известные коды статусов.
+func StatusCodes() []StatusCode {
+	return []StatusCode{CodeFinished, CodeNotStarted, CodeNotFinished, CodeDisqualified, CodeLapped, CodeProvisional}
+}
+
+// StatusCode возвращает код результата по текущему состоянию участника.
+func (c *Competitor) StatusCode() StatusCode {
+	switch c.Status {
+	case Finished:
+		return CodeFinished
+	case NotStarted:
+		return CodeNotStarted
+	case NotFinished:
+		return CodeNotFinished
+	case Disqualified:
+		return CodeDisqualified
+	case Lapped:
+		return CodeLapped
+	default:
+		return CodeProvisional
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+// Поле реестра, по которому спортсмены объединяются в команды
+const (
+	TeamByNation = "nation"
+	TeamByClub   = "club"
+)
+
+// Способы подсчёта командного результата
+const (
+	TeamScoringTime   = "time"   // Сумма времён лучших участников, меньше - лучше
+	TeamScoringPoints = "points" // Сумма очков за места лучших участников, больше - лучше
+)
+
+// TeamScoring - правила командного зачёта гонки
+type TeamScoring struct {
+	By           string // nation или club
+	Scoring      string // time или points
+	Best         int    // Сколько лучших спортсменов команды идут в зачёт
+	MinFinishers int    // Минимум спортсменов с результатом для попадания в зачёт
+	Points       []int  // Очки за места при подсчёте по очкам
+}
+
+// PointsFor возвращает очки за место в гонке
+func (t *TeamScoring) PointsFor(rank int) int {
+	if rank < 1 || rank > len(t.Points) {
+		return 0
+	}
+	return t.Points[rank-1]
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimelineKind - вид записи в хронологии участника
+type TimelineKind string
+
+const (
+	TimelineEvent   TimelineKind = "event"   // Входящее событие
+	TimelineStatus  TimelineKind = "status"  // Смена статуса
+	TimelineDerived TimelineKind = "derived" // Вычисленное событие: круг, рубеж, штрафной круг, финиш
+	TimelineWarning TimelineKind = "warning" // Замечание проверки данных
+)
+
+// TimelineEntry - запись хронологии участника
+type TimelineEntry struct {
+	Time time.Time
+	Kind TimelineKind
+	Text string
+
+	From, To CompetitorStatus // Статусы до и после для записи TimelineStatus
+}
+
+// Note добавляет запись в хронологию участника.
+func (c *Competitor) Note(t time.Time, kind TimelineKind, text string) {
+	c.Timeline = append(c.Timeline, TimelineEntry{Time: t, Kind: kind, Text: text})
+}
+
+// noteStatus фиксирует смену статуса со временем последней записи хронологии,
+// то есть события, которое к ней привело.
+func (c *Competitor) noteStatus(from, to CompetitorStatus) {
+	var t time.Time
+	if n := len(c.Timeline); n > 0 {
+		t = c.Timeline[n-1].Time
+	}
+	c.Timeline = append(c.Timeline, TimelineEntry{
+		Time: t,
+		Kind: TimelineStatus,
+		Text: fmt.Sprintf("%s -> %s", from, to),
+		From: from,
+		To:   to,
+	})
+}
+
+var statusNames = map[CompetitorStatus]string{
+	Registered:    "Registered",
+	OnStart:       "OnStart",
+	Racing:        "Racing",
+	InFiringRange: "InFiringRange",
+	InPenalty:     "InPenalty",
+	Finished:      "Finished",
+	Disqualified:  "Disqualified",
+	NotStarted:    "NotStarted",
+	NotFinished:   "NotFinished",
+	Lapped:        "Lapped",
+}
+
+func (s CompetitorStatus) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+var eventNames = map[EventType]string{
+	CompetitorRegistered: "registered",
+	StartTimeSet:         "start time set",
+	OnStartLine:          "on the start line",
+	Started:              "started",
+	OnFiringRange:        "on the firing range",
+	TargetHit:            "target hit",
+	LeftFiringRange:      "left the firing range",
+	EnteredPenalty:       "entered the penalty laps",
+	LeftPenalty:          "left the penalty laps",
+	LapFinished:          "main lap finished",
+	CannotContinue:       "cannot continue",
+	ShotFired:            "shot fired",
+	PassedSplit:          "passed split",
+	JuryDisqualified:     "jury: disqualified",
+	JuryTimeAdjusted:     "jury: time adjusted",
+	JuryReinstated:       "jury: reinstated",
+	JuryResultsState:     "jury: results state",
+}
+
+func (t EventType) String() string {
+	if name, ok := eventNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("event %d", int(t))
+}
@@ -0,0 +1,133 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultLang - язык по умолчанию; его каталог дополняет остальные недостающими сообщениями
+const DefaultLang = "en"
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// Catalog - сообщения одного языка по ключам. Сообщение - строка формата fmt.
+// Нулевой (nil) каталог выводит сообщения языка по умолчанию.
+type Catalog struct {
+	lang     string
+	messages map[string]string
+	fallback *Catalog
+}
+
+// catalogFile - файл каталога: {"lang": "de", "messages": {"key": "text", ...}}
+type catalogFile struct {
+	Lang     string            `json:"lang"`
+	Messages map[string]string `json:"messages"`
+}
+
+var defaultCatalog = mustLoadBuiltin(DefaultLang)
+
+// Default возвращает каталог языка по умолчанию.
+func Default() *Catalog {
+	return defaultCatalog
+}
+
+// Load возвращает каталог по коду встроенного языка (en, ru, ...) или по пути к файлу каталога (.json).
+// Новый язык подключается файлом каталога (-lang path/de.json) без изменения кода; встроенные языки лежат в locales.
+func Load(lang string) (*Catalog, error) {
+	if lang == "" || lang == DefaultLang {
+		return defaultCatalog, nil
+	}
+	if strings.EqualFold(filepath.Ext(lang), ".json") {
+		data, err := os.ReadFile(lang)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message catalog: %w", err)
+		}
+		name := strings.TrimSuffix(filepath.Base(lang), filepath.Ext(lang))
+		return parse(data, name, defaultCatalog)
+	}
+
+	data, err := locales.ReadFile(path.Join("locales", lang+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown language %q (available: %s)", lang, strings.Join(Languages(), ", "))
+	}
+	return parse(data, lang, defaultCatalog)
+}
+
+// Languages возвращает коды встроенных языков.
+func Languages() []string {
+	entries, _ := fs.Glob(locales, "locales/*.json")
+	langs := make([]string, len(entries))
+	for i, e := range entries {
+		langs[i] = strings.TrimSuffix(path.Base(e), ".json")
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+func mustLoadBuiltin(lang string) *Catalog {
+	data, err := locales.ReadFile(path.Join("locales", lang+".json"))
+	if err != nil {
+		panic(err)
+	}
+	c, err := parse(data, lang, nil)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func parse(data []byte, name string, fallback *Catalog) (*Catalog, error) {
+	var file catalogFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid message catalog %q: %w", name, err)
+	}
+	if len(file.Messages) == 0 {
+		return nil, fmt.Errorf("message catalog %q has no messages", name)
+	}
+	lang := file.Lang
+	if lang == "" {
+		lang = name
+	}
+	return &Catalog{lang: lang, messages: file.Messages, fallback: fallback}, nil
+}
+
+// Lang возвращает код языка каталога.
+func (c *Catalog) Lang() string {
+	if c == nil {
+		return defaultCatalog.lang
+	}
+	return c.lang
+}
+
+// Lookup возвращает сообщение без форматирования; ok = false, если ключа нет ни в одном каталоге.
+func (c *Catalog) Lookup(key string) (string, bool) {
+	if c == nil {
+		c = defaultCatalog
+	}
+	for ; c != nil; c = c.fallback {
+		if msg, ok := c.messages[key]; ok {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+// T возвращает сообщение по ключу, подставляя args; неизвестный ключ выводится как есть.
+func (c *Catalog) T(key string, args ...any) string {
+	msg, ok := c.Lookup(key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
@@ -4,8 +4,14 @@ import (
 	"fmt"
 	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
 	"github.com/BiathlonRaceProto-Yadro/pkg/utils"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+// Количество мишеней на рубеже по умолчанию
+const defaultShotsPerStage = 5
+
 type ConfigAdapter struct{}
 
 func NewConfigAdapter() *ConfigAdapter {
@@ -27,12 +33,248 @@ func (a *ConfigAdapter) ToDomain(raw RawConfig) (*models.Config, error) {
 		return nil, fmt.Errorf("laps must be positive")
 	}
 
-	return models.NewConfig(
+	shots := raw.ShotsPerStage
+	if shots == 0 {
+		shots = defaultShotsPerStage
+	}
+	if shots < 0 {
+		return nil, fmt.Errorf("shots per stage must be positive")
+	}
+	if raw.SpareRounds < 0 {
+		return nil, fmt.Errorf("spare rounds must not be negative")
+	}
+
+	format := raw.Format
+	if format == "" {
+		format = models.FormatSprint
+	}
+	sequence, err := models.DefaultShootingSequence(format)
+	if err != nil {
+		return nil, err
+	}
+	if raw.ShootingSequence != "" {
+		if sequence, err = models.ParseShootingSequence(raw.ShootingSequence); err != nil {
+			return nil, fmt.Errorf("invalid shooting sequence: %w", err)
+		}
+	}
+
+	splits, err := a.splitsToDomain(raw.Splits, raw.LapLen)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := a.statusLabelsToDomain(raw.StatusLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	switch raw.LappedRule {
+	case "":
+	case models.LappedPull, models.LappedFlag:
+		if format != models.FormatPursuit && format != models.FormatMassStart {
+			return nil, fmt.Errorf("lapped rule applies only to pursuit and mass start")
+		}
+	default:
+		return nil, fmt.Errorf("unknown lapped rule %q", raw.LappedRule)
+	}
+
+	cfg := models.NewConfig(
 		raw.Laps,
 		raw.LapLen,
 		raw.PenaltyLen,
 		raw.FiringLines,
 		startTime,
 		delta,
-	), nil
+		shots,
+		raw.SpareRounds,
+	)
+	cfg.Format = format
+	cfg.ShootingSequence = sequence
+	cfg.Splits = splits
+	cfg.StatusLabels = labels
+	cfg.LappedRule = raw.LappedRule
+
+	if cfg.Categories, err = a.categoriesToDomain(raw.Categories, cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Teams, err = a.teamsToDomain(raw.Teams); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// teamsToDomain проверяет правила командного зачёта и подставляет значения по умолчанию:
+// три лучших спортсмена; для подсчёта по времени в зачёт идут только полные команды.
+func (a *ConfigAdapter) teamsToDomain(raw *RawTeamScoring) (*models.TeamScoring, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	teams := &models.TeamScoring{
+		By:           raw.By,
+		Scoring:      raw.Scoring,
+		Best:         raw.Best,
+		MinFinishers: raw.MinFinishers,
+		Points:       raw.Points,
+	}
+	if teams.By == "" {
+		teams.By = models.TeamByNation
+	}
+	if teams.By != models.TeamByNation && teams.By != models.TeamByClub {
+		return nil, fmt.Errorf("teams: unknown grouping %q", raw.By)
+	}
+	if teams.Scoring == "" {
+		teams.Scoring = models.TeamScoringTime
+	}
+	if teams.Scoring != models.TeamScoringTime && teams.Scoring != models.TeamScoringPoints {
+		return nil, fmt.Errorf("teams: unknown scoring %q", raw.Scoring)
+	}
+	if teams.Best == 0 {
+		teams.Best = 3
+	}
+	if teams.Best < 0 || teams.MinFinishers < 0 {
+		return nil, fmt.Errorf("teams: best and minFinishers must be positive")
+	}
+	if teams.MinFinishers == 0 {
+		teams.MinFinishers = 1
+		if teams.Scoring == models.TeamScoringTime {
+			teams.MinFinishers = teams.Best
+		}
+	}
+	if teams.Scoring == models.TeamScoringTime && teams.MinFinishers < teams.Best {
+		return nil, fmt.Errorf("teams: time scoring needs at least %d finishers", teams.Best)
+	}
+	if len(teams.Points) == 0 {
+		teams.Points = models.DefaultPointsTable
+	}
+	for i, p := range teams.Points {
+		if p < 0 {
+			return nil, fmt.Errorf("teams: points for place %d must not be negative", i+1)
+		}
+	}
+	return teams, nil
+}
+
+func (a *ConfigAdapter) categoriesToDomain(raw []RawCategory, base *models.Config) ([]models.Category, error) {
+	categories := make([]models.Category, 0, len(raw))
+	for _, rc := range raw {
+		if rc.Name == "" {
+			return nil, fmt.Errorf("category name is required")
+		}
+		for _, other := range categories {
+			if other.Name == rc.Name {
+				return nil, fmt.Errorf("duplicate category %q", rc.Name)
+			}
+		}
+
+		course := *base
+		course.Categories = nil
+		if rc.Laps > 0 {
+			course.Laps = rc.Laps
+		}
+		if rc.LapLen > 0 {
+			course.LapLen = rc.LapLen
+		}
+		// Отсечки общие для всех категорий, поэтому должны укладываться и в круг категории
+		for _, sp := range course.Splits {
+			if sp.Distance >= course.LapLen {
+				return nil, fmt.Errorf("category %q: split point %q distance must be within the lap", rc.Name, sp.ID)
+			}
+		}
+		if rc.PenaltyLen > 0 {
+			course.PenaltyLen = rc.PenaltyLen
+		}
+		if rc.FiringLines > 0 {
+			course.FiringLines = rc.FiringLines
+		}
+		if rc.Start != "" {
+			start, err := utils.ParseTime(rc.Start)
+			if err != nil {
+				return nil, fmt.Errorf("category %q: invalid start time: %w", rc.Name, err)
+			}
+			course.Start = start
+		}
+		if rc.StartDelta != "" {
+			delta, err := utils.ParseDuration(rc.StartDelta)
+			if err != nil {
+				return nil, fmt.Errorf("category %q: invalid start delta: %w", rc.Name, err)
+			}
+			course.StartDelta = delta
+		}
+
+		category := models.Category{Name: rc.Name, Course: &course}
+		if rc.Bibs != "" {
+			from, to, err := parseBibRange(rc.Bibs)
+			if err != nil {
+				return nil, fmt.Errorf("category %q: %w", rc.Name, err)
+			}
+			for _, other := range categories {
+				if other.BibFrom > 0 && from <= other.BibTo && to >= other.BibFrom {
+					return nil, fmt.Errorf("category %q bibs overlap with %q", rc.Name, other.Name)
+				}
+			}
+			category.BibFrom, category.BibTo = from, to
+		}
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+// parseBibRange разбирает диапазон номеров вида "1-20".
+func parseBibRange(raw string) (int, int, error) {
+	fromStr, toStr, ok := strings.Cut(raw, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("bibs must look like from-to")
+	}
+	from, err := strconv.Atoi(strings.TrimSpace(fromStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid bib range: %w", err)
+	}
+	to, err := strconv.Atoi(strings.TrimSpace(toStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid bib range: %w", err)
+	}
+	if from <= 0 || to < from {
+		return 0, 0, fmt.Errorf("invalid bib range %q", raw)
+	}
+	return from, to, nil
+}
+
+func (a *ConfigAdapter) statusLabelsToDomain(raw map[string]string) (map[models.StatusCode]string, error) {
+	labels := make(map[models.StatusCode]string, len(raw))
+	for code, label := range raw {
+		known := false
+		for _, c := range models.StatusCodes() {
+			if string(c) == code {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown status code %q", code)
+		}
+		labels[models.StatusCode(code)] = label
+	}
+	return labels, nil
+}
+
+func (a *ConfigAdapter) splitsToDomain(raw []RawSplitPoint, lapLen int) ([]models.SplitPoint, error) {
+	splits := make([]models.SplitPoint, 0, len(raw))
+	seen := make(map[string]bool)
+	for _, sp := range raw {
+		if sp.ID == "" {
+			return nil, fmt.Errorf("split point id is required")
+		}
+		if seen[sp.ID] {
+			return nil, fmt.Errorf("duplicate split point %q", sp.ID)
+		}
+		if sp.Distance <= 0 || sp.Distance >= lapLen {
+			return nil, fmt.Errorf("split point %q distance must be within the lap", sp.ID)
+		}
+		seen[sp.ID] = true
+		splits = append(splits, models.SplitPoint{ID: sp.ID, Distance: sp.Distance})
+	}
+	sort.Slice(splits, func(i, j int) bool {
+		return splits[i].Distance < splits[j].Distance
+	})
+	return splits, nil
 }
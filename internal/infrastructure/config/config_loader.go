@@ -14,6 +14,48 @@ type RawConfig struct {
 	FiringLines int    `json:"firingLines"`
 	Start       string `json:"start"`
 	StartDelta  string `json:"startDelta"`
+
+	ShotsPerStage int `json:"shotsPerStage"`
+	SpareRounds   int `json:"spareRounds"`
+
+	Format           string `json:"format"`
+	ShootingSequence string `json:"shootingSequence"`
+
+	Splits []RawSplitPoint `json:"splits"`
+
+	StatusLabels map[string]string `json:"statusLabels"`
+
+	LappedRule string `json:"lappedRule"`
+
+	Categories []RawCategory `json:"categories"`
+
+	Teams *RawTeamScoring `json:"teams"`
+}
+
+// RawTeamScoring - командный зачёт: {"by": "nation", "scoring": "time", "best": 3, "minFinishers": 3}
+type RawTeamScoring struct {
+	By           string `json:"by"`
+	Scoring      string `json:"scoring"`
+	Best         int    `json:"best"`
+	MinFinishers int    `json:"minFinishers"`
+	Points       []int  `json:"points"`
+}
+
+// RawCategory - категория; незаданные параметры трассы берутся из общей конфигурации
+type RawCategory struct {
+	Name        string `json:"name"`
+	Bibs        string `json:"bibs"` // Диапазон номеров "1-20"
+	Laps        int    `json:"laps"`
+	LapLen      int    `json:"lapLen"`
+	PenaltyLen  int    `json:"penaltyLen"`
+	FiringLines int    `json:"firingLines"`
+	Start       string `json:"start"`
+	StartDelta  string `json:"startDelta"`
+}
+
+type RawSplitPoint struct {
+	ID       string `json:"id"`
+	Distance int    `json:"distance"`
 }
 
 type JSONConfigLoader struct{}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"os"
+	"path/filepath"
+)
+
+type RawChampionship struct {
+	Name      string         `json:"name"`
+	Races     []RawRaceEntry `json:"races"`
+	Points    []int          `json:"points"`
+	DropWorst int            `json:"dropWorst"`
+}
+
+type RawRaceEntry struct {
+	Name     string `json:"name"`
+	Config   string `json:"config"`
+	Events   string `json:"events"`
+	Results  string `json:"results"`
+	Athletes string `json:"athletes"`
+	Format   string `json:"format"`
+	Category string `json:"category"`
+}
+
+type JSONManifestLoader struct{}
+
+func NewJSONManifestLoader() *JSONManifestLoader {
+	return &JSONManifestLoader{}
+}
+
+// LoadManifest читает манифест чемпионата. Относительные пути считаются от каталога манифеста.
+func (l *JSONManifestLoader) LoadManifest(path string) (*models.Championship, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest file: %w", err)
+	}
+
+	var raw RawChampionship
+	if err := json.Unmarshal(file, &raw); err != nil {
+		return nil, fmt.Errorf("invalid manifest format: %w", err)
+	}
+	if len(raw.Races) == 0 {
+		return nil, fmt.Errorf("manifest has no races")
+	}
+
+	points := raw.Points
+	if len(points) == 0 {
+		points = models.DefaultPointsTable
+	}
+	for i, p := range points {
+		if p < 0 {
+			return nil, fmt.Errorf("points for place %d must not be negative", i+1)
+		}
+	}
+	if raw.DropWorst < 0 || raw.DropWorst >= len(raw.Races) {
+		return nil, fmt.Errorf("dropWorst must be between 0 and %d", len(raw.Races)-1)
+	}
+
+	dir := filepath.Dir(path)
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+
+	champ := &models.Championship{Name: raw.Name, Points: points, DropWorst: raw.DropWorst}
+	for i, r := range raw.Races {
+		hasEvents := r.Config != "" && r.Events != ""
+		if hasEvents == (r.Results != "") {
+			return nil, fmt.Errorf("race %d: either config and events or results are required", i+1)
+		}
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("Race %d", i+1)
+		}
+		champ.Races = append(champ.Races, models.RaceEntry{
+			Name:     name,
+			Config:   resolve(r.Config),
+			Events:   resolve(r.Events),
+			Results:  resolve(r.Results),
+			Athletes: resolve(r.Athletes),
+			Format:   r.Format,
+			Category: r.Category,
+		})
+	}
+	return champ, nil
+}
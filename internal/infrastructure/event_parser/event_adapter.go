@@ -71,6 +71,22 @@ func (a *EventAdapter) ParseEvent(
 		if _, err := strconv.Atoi(params[0]); err != nil {
 			return nil, fmt.Errorf("invalid target number: %w", err)
 		}
+	case models.PassedSplit:
+		if len(params) != 1 {
+			return nil, fmt.Errorf("event 13 requires split point id")
+		}
+	case models.JuryDisqualified:
+		if len(params) < 1 {
+			return nil, fmt.Errorf("event 14 requires rule reference")
+		}
+	case models.JuryTimeAdjusted:
+		if len(params) < 1 {
+			return nil, fmt.Errorf("event 15 requires penalty time")
+		}
+	case models.JuryResultsState:
+		if len(params) < 1 {
+			return nil, fmt.Errorf("event 17 requires results state")
+		}
 	default:
 
 	}
@@ -0,0 +1,60 @@
+package registry
+
+import (
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"strconv"
+)
+
+type RegistryAdapter struct{}
+
+func NewRegistryAdapter() *RegistryAdapter {
+	return &RegistryAdapter{}
+}
+
+// ParseRecord разбирает строку CSV в запись реестра.
+func (a *RegistryAdapter) ParseRecord(bib, name, nation, club, birthYear, category string) (RawAthlete, error) {
+	n, err := strconv.Atoi(bib)
+	if err != nil {
+		return RawAthlete{}, fmt.Errorf("invalid bib: %w", err)
+	}
+
+	year := 0
+	if birthYear != "" {
+		if year, err = strconv.Atoi(birthYear); err != nil {
+			return RawAthlete{}, fmt.Errorf("invalid birth year: %w", err)
+		}
+	}
+
+	return RawAthlete{
+		Bib:       n,
+		Name:      name,
+		Nation:    nation,
+		Club:      club,
+		BirthYear: year,
+		Category:  category,
+	}, nil
+}
+
+func (a *RegistryAdapter) ToDomain(raw []RawAthlete) (*models.Registry, error) {
+	athletes := make([]models.Athlete, 0, len(raw))
+	seen := make(map[int]bool)
+	for i, r := range raw {
+		if r.Bib <= 0 {
+			return nil, fmt.Errorf("athlete %d: bib must be positive, got %d", i+1, r.Bib)
+		}
+		if seen[r.Bib] {
+			return nil, fmt.Errorf("duplicate bib %d in registry", r.Bib)
+		}
+		seen[r.Bib] = true
+		athletes = append(athletes, models.Athlete{
+			Bib:       r.Bib,
+			Name:      r.Name,
+			Nation:    r.Nation,
+			Club:      r.Club,
+			BirthYear: r.BirthYear,
+			Category:  r.Category,
+		})
+	}
+	return models.NewRegistry(athletes), nil
+}
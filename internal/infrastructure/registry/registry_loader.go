@@ -0,0 +1,104 @@
+package registry
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/domain/models"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type RawAthlete struct {
+	Bib       int    `json:"bib"`
+	Name      string `json:"name"`
+	Nation    string `json:"nation"`
+	Club      string `json:"club"`
+	BirthYear int    `json:"birthYear"`
+	Category  string `json:"category"`
+}
+
+// FileRegistryLoader загружает реестр из CSV или JSON в зависимости от расширения файла
+type FileRegistryLoader struct{}
+
+func NewFileRegistryLoader() *FileRegistryLoader {
+	return &FileRegistryLoader{}
+}
+
+func (l *FileRegistryLoader) LoadRegistry(path string) (*models.Registry, error) {
+	var (
+		raw []RawAthlete
+		err error
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		raw, err = l.readJSON(path)
+	case ".csv":
+		raw, err = l.readCSV(path)
+	default:
+		return nil, fmt.Errorf("unsupported registry format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewRegistryAdapter().ToDomain(raw)
+}
+
+func (l *FileRegistryLoader) readJSON(path string) ([]RawAthlete, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry file: %w", err)
+	}
+
+	var raw []RawAthlete
+	if err := json.Unmarshal(file, &raw); err != nil {
+		return nil, fmt.Errorf("invalid registry format: %w", err)
+	}
+	return raw, nil
+}
+
+// CSV с заголовком: bib,name,nation,club,birthYear,category (порядок колонок произвольный)
+func (l *FileRegistryLoader) readCSV(path string) ([]RawAthlete, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry format: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("registry file is empty")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["bib"]; !ok {
+		return nil, fmt.Errorf("registry header must contain bib column")
+	}
+
+	raw := make([]RawAthlete, 0, len(records)-1)
+	for line, record := range records[1:] {
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		athlete, err := NewRegistryAdapter().ParseRecord(get("bib"), get("name"), get("nation"),
+			get("club"), get("birthyear"), get("category"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+2, err)
+		}
+		raw = append(raw, athlete)
+	}
+	return raw, nil
+}
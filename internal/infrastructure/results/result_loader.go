@@ -0,0 +1,31 @@
+package results
+
+import (
+	"fmt"
+	"github.com/BiathlonRaceProto-Yadro/internal/application"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileResultLoader загружает сохранённый результат гонки (XML или JSON) по расширению файла
+type FileResultLoader struct{}
+
+func NewFileResultLoader() *FileResultLoader {
+	return &FileResultLoader{}
+}
+
+func (l *FileResultLoader) LoadResult(path string) (*application.RaceResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results file: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xml":
+		return application.ParseResultXML(data)
+	case ".json":
+		return application.ParseResultJSON(data)
+	default:
+		return nil, fmt.Errorf("unsupported results format %q", filepath.Ext(path))
+	}
+}